@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"github.com/aspin/solana-trader-tui/flags"
+	"github.com/aspin/solana-trader-tui/headless"
 	applog "github.com/aspin/solana-trader-tui/log"
 	"github.com/aspin/solana-trader-tui/program"
 	"github.com/aspin/solana-trader-tui/store"
@@ -11,6 +12,8 @@ import (
 	"os"
 )
 
+var logFile *os.File
+
 func main() {
 	app := &cli.App{
 		Name:  "solana-trader-terminal-ui",
@@ -19,7 +22,10 @@ func main() {
 			flags.LogFile,
 			flags.ConfigFile,
 		},
-		Action: run,
+		Before:   initLog,
+		After:    closeLog,
+		Action:   run,
+		Commands: headless.Commands(),
 	}
 
 	if err := app.Run(os.Args); err != nil {
@@ -28,18 +34,27 @@ func main() {
 	}
 }
 
-func run(c *cli.Context) error {
+func initLog(c *cli.Context) error {
 	logConfig := applog.NewConfigFromCLI(c)
-	logFile, err := applog.Init(logConfig)
+
+	var err error
+	logFile, err = applog.Init(logConfig)
 	if err != nil {
 		return fmt.Errorf("could not initialize logger: %w", err)
 	}
-	defer func(logFile *os.File) {
+	return nil
+}
+
+func closeLog(c *cli.Context) error {
+	if logFile != nil {
 		_ = logFile.Close()
-	}(logFile)
+	}
+	return nil
+}
 
+func run(c *cli.Context) error {
 	appStore := store.NewFromFile(c.String(flags.ConfigFile.Name))
 	p := program.New(appStore)
-	_, err = p.Run()
+	_, err := p.Run()
 	return err
 }
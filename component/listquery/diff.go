@@ -0,0 +1,134 @@
+package listquery
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+	"io"
+	"strings"
+)
+
+var (
+	addedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	changedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	removedStyle = errorStyle
+)
+
+// Keyed is implemented by items with an identity other than their filter value, so polls can tell a changed row from
+// a removed and an added one
+type Keyed interface {
+	Key() string
+}
+
+type change int
+
+const (
+	unchanged change = iota
+	added
+	changed
+	removed
+)
+
+// diff is how each row, by key, differs from the previous poll. It is shared with the delegate rendering the rows.
+type diff struct {
+	rows map[string]change
+}
+
+func (d *diff) count(c change) int {
+	n := 0
+	for _, row := range d.rows {
+		if row == c {
+			n++
+		}
+	}
+	return n
+}
+
+func itemKey(item list.Item) string {
+	if keyed, ok := item.(Keyed); ok {
+		return keyed.Key()
+	}
+	return item.FilterValue()
+}
+
+// itemText is what is compared to tell whether a row changed
+func itemText(item list.Item) string {
+	if row, ok := item.(Row); ok {
+		return strings.Join(row.Cells(), "\t")
+	}
+	if item, ok := item.(list.DefaultItem); ok {
+		return item.Title() + "\n" + item.Description()
+	}
+	return item.FilterValue()
+}
+
+// compare records how items differ from previous, and returns items with the removed rows kept at their previous
+// positions, so they can be shown until the next poll
+func (d *diff) compare(previous, items []list.Item) []list.Item {
+	d.rows = make(map[string]change)
+
+	texts := make(map[string]string, len(previous))
+	for _, item := range previous {
+		texts[itemKey(item)] = itemText(item)
+	}
+
+	keys := make(map[string]bool, len(items))
+	for _, item := range items {
+		key := itemKey(item)
+		keys[key] = true
+		if text, ok := texts[key]; !ok {
+			d.rows[key] = added
+		} else if text != itemText(item) {
+			d.rows[key] = changed
+		}
+	}
+
+	merged := append([]list.Item(nil), items...)
+	for i, item := range previous {
+		key := itemKey(item)
+		if keys[key] {
+			continue
+		}
+		d.rows[key] = removed
+		if i > len(merged) {
+			i = len(merged)
+		}
+		merged = append(merged[:i], append([]list.Item{item}, merged[i:]...)...)
+	}
+	return merged
+}
+
+func (d *diff) removed(item list.Item) bool {
+	return item != nil && d.rows[itemKey(item)] == removed
+}
+
+// diffDelegate renders rows with the wrapped delegate, marking those added, changed or removed since the previous poll
+type diffDelegate struct {
+	list.ItemDelegate
+	diff *diff
+}
+
+func (d diffDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	var b strings.Builder
+	d.ItemDelegate.Render(&b, m, index, item)
+
+	marker := "  "
+	switch d.diff.rows[itemKey(item)] {
+	case added:
+		marker = addedStyle.Render("+ ")
+	case changed:
+		marker = changedStyle.Render("~ ")
+	case removed:
+		marker = removedStyle.Render("- ")
+	}
+
+	lines := strings.Split(b.String(), "\n")
+	for i := range lines {
+		if i == 0 {
+			lines[i] = marker + lines[i]
+		} else {
+			lines[i] = "  " + lines[i]
+		}
+	}
+	fmt.Fprint(w, strings.Join(lines, "\n"))
+}
@@ -1,6 +1,8 @@
 package listquery
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
@@ -9,6 +11,8 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
 var (
@@ -16,6 +20,7 @@ var (
 	focusedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("86"))
 	listStyle    = lipgloss.NewStyle().Margin(1, 2)
 	noStyle      = lipgloss.NewStyle()
+	statusStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
 )
 
 type viewState int
@@ -26,36 +31,131 @@ const (
 	vsShow
 )
 
-type queryFn func([]string)
+// DefaultTimeout bounds each query unless Model.Timeout is changed
+const DefaultTimeout = 30 * time.Second
+
+// maxHistory is how many submitted queries are recalled
+const maxHistory = 50
+
+// DefaultPollInterval is how often shown results are refreshed unless Model.PollInterval is changed
+const DefaultPollInterval = 10 * time.Second
+
+// queryFn runs the query with the input values. ctx is cancelled when the query times out or is cancelled.
+type queryFn func(ctx context.Context, values []string) ([]list.Item, error)
+
+// recordFn is given the query history, most recent first, each time a query is submitted, e.g. to persist it
+type recordFn func(history [][]string)
+
+// lastQueryID numbers the queries of all models, so a model only accepts the results of its latest query
+var lastQueryID int64
 
 type ResultMsg struct {
 	Items []list.Item
+
+	queryID int64
 }
 
 type ErrorMsg struct {
 	Err error
+
+	queryID int64
+}
+
+// pollMsg is due when the results of the query it carries should be refreshed
+type pollMsg struct {
+	queryID int64
+}
+
+// KeyMap defines the bindings used to move between and submit the query inputs
+type KeyMap struct {
+	NextField key.Binding
+	PrevField key.Binding
+	Submit    key.Binding
+	Cancel    key.Binding
+	Poll      key.Binding
+
+	// Sort sorts a table by the column at the index of the pressed key among the binding's keys
+	Sort key.Binding
+
+	// HistoryPrev and HistoryNext recall earlier queries into the inputs while the first has focus, taking precedence
+	// over moving between fields there; HistoryNext only does so once a query is recalled
+	HistoryPrev key.Binding
+	HistoryNext key.Binding
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		NextField: key.NewBinding(key.WithKeys("tab", "down"), key.WithHelp("tab/↓", "next field")),
+		PrevField: key.NewBinding(key.WithKeys("shift+tab", "up"), key.WithHelp("shift+tab/↑", "previous field")),
+		Submit:    key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "next field / submit")),
+		Cancel:    key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel")),
+		Poll:      key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "pause/resume auto-refresh")),
+		Sort:      key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"), key.WithHelp("1-9", "sort by column")),
+
+		HistoryPrev: key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "previous query")),
+		HistoryNext: key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "next query")),
+	}
 }
 
 type Model struct {
+	KeyMap KeyMap
+
+	// Timeout bounds each query; zero disables the timeout
+	Timeout time.Duration
+
+	// PollInterval is how often shown results are refreshed by re-running their query; zero disables polling
+	PollInterval time.Duration
+
 	focusIndex int
 	inputs     []textinput.Model
 
+	// history is the submitted input values, most recent first. recalled indexes the entry shown in the inputs, or is
+	// -1 while draft, the values typed before recalling, is shown.
+	history  [][]string
+	recalled int
+	draft    []string
+	record   recordFn
+
 	spinner spinner.Model
 	list    list.Model
 	query   queryFn
 
+	// queryID, ctx and cancel belong to the in-flight query
+	queryID int64
+	ctx     context.Context
+	cancel  context.CancelFunc
+
+	// paused stops polling; polled is when the shown results were last refreshed, and diff how they changed
+	paused bool
+	polled time.Time
+	diff   *diff
+
+	// table is nil unless results are shown as a table
+	table *table
+
+	// reselect is the key of the row to put the cursor back on once the filter has been re-applied
+	reselect string
+
 	state viewState
 	err   error
 }
 
-func New(inputs []textinput.Model, spinnerType spinner.Spinner, l list.Model, query queryFn) Model {
+// New creates a model showing results with delegate, which is wrapped to mark the rows changed by polls
+func New(inputs []textinput.Model, spinnerType spinner.Spinner, delegate list.ItemDelegate, query queryFn) Model {
+	d := &diff{}
+	l := list.New([]list.Item{}, diffDelegate{ItemDelegate: delegate, diff: d}, 0, 0)
 	l.SetShowTitle(false)
 	return Model{
-		inputs:  inputs,
-		spinner: spinner.New(spinner.WithSpinner(spinnerType)),
-		list:    l,
-		state:   vsInput,
-		query:   query,
+		KeyMap:       DefaultKeyMap(),
+		Timeout:      DefaultTimeout,
+		PollInterval: DefaultPollInterval,
+		inputs:       inputs,
+		spinner:      spinner.New(spinner.WithSpinner(spinnerType)),
+		list:         l,
+		state:        vsInput,
+		query:        query,
+		diff:         d,
+		recalled:     -1,
 	}
 }
 
@@ -63,22 +163,54 @@ func (m *Model) SetQuery(query queryFn) {
 	m.query = query
 }
 
+// SetHistory sets the earlier queries recalled in the inputs, most recent first, and fills the inputs with the latest.
+// record is given the updated history each time a query is submitted.
+func (m *Model) SetHistory(history [][]string, record recordFn) {
+	m.history = history
+	m.record = record
+	if len(history) > 0 {
+		m.setInputValues(history[0])
+	}
+}
+
+// SetColumns shows results as a table with one line per result; items should implement Row
+func (m *Model) SetColumns(columns ...Column) {
+	m.table = &table{columns: columns, sortColumn: -1}
+	m.list.SetDelegate(diffDelegate{ItemDelegate: tableDelegate{table: m.table}, diff: m.diff})
+
+	// the title bar holds the column headers, and the status line the row counts
+	m.list.SetShowTitle(true)
+	m.list.SetShowStatusBar(false)
+	m.list.Styles.Title = noStyle
+	m.list.Styles.TitleBar = noStyle
+}
+
+// SortBy sorts a table by column, largest first for numeric columns, until another column is picked
+func (m *Model) SortBy(column int) {
+	m.table.sortColumn = -1
+	m.table.sortBy(column)
+}
+
+// Init shows the inputs again. They keep the values of the last visit, so the query can be re-submitted as is.
 func (m *Model) Init(width, height int) tea.Cmd {
+	m.done()
 	m.focusIndex = 0
 	m.state = vsInput
-	for _, input := range m.inputs {
-		input.SetValue("")
-	}
+	m.recalled = -1
+	m.draft = nil
 	m.focusInputs()
 
 	m.err = nil
+	m.paused = false
+	m.diff.rows = nil
 	m.setSize(width, height)
 	return textinput.Blink
 }
 
 func (m *Model) setSize(width, height int) {
+	// leave room for the change markers and the polling status line
 	h, v := listStyle.GetFrameSize()
-	m.list.SetSize(width-h, height-v)
+	m.list.SetSize(width-h-2, height-v-1)
 }
 
 func (m *Model) focusInputs() tea.Cmd {
@@ -109,19 +241,21 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd, bool) {
 	case vsInput:
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
-			switch k := msg.Type; k {
-			case tea.KeyTab, tea.KeyShiftTab, tea.KeyEnter, tea.KeyUp, tea.KeyDown:
-				if msg.Type == tea.KeyEnter && m.focusIndex == len(m.inputs) {
+			if m.recalls(msg) {
+				m.recall(key.Matches(msg, m.KeyMap.HistoryPrev))
+				return m, nil, false
+			}
+			if key.Matches(msg, m.KeyMap.NextField, m.KeyMap.PrevField, m.KeyMap.Submit) {
+				if key.Matches(msg, m.KeyMap.Submit) && m.focusIndex == len(m.inputs) {
 					if err := m.validateInputs(); err == nil {
-						m.state = vsLoading
-						go m.query(m.inputValues())
-						return m, m.spinner.Tick, false
+						m.remember()
+						return m, m.run(), false
 					} else {
 						m.err = err
 					}
 				}
 
-				m.updateCursor(k)
+				m.updateCursor(key.Matches(msg, m.KeyMap.PrevField))
 				cmd = m.focusInputs()
 				return m, cmd, false
 			}
@@ -133,11 +267,32 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd, bool) {
 		case spinner.TickMsg:
 			m.spinner, cmd = m.spinner.Update(msg)
 			return m, cmd, false
+		case tea.KeyMsg:
+			if key.Matches(msg, m.KeyMap.Cancel) {
+				m.Cancel()
+				return m, tea.Batch(m.focusInputs(), textinput.Blink), false
+			}
 		case ResultMsg:
-			m.list.SetItems(msg.Items)
+			if msg.queryID != m.queryID {
+				// overtaken by a later query
+				return m, nil, false
+			}
+			m.done()
+			m.err = nil
+			m.diff.rows = nil
+			cmd = m.setItems(msg.Items)
+			m.polled = time.Now()
 			m.state = vsShow
+			return m, tea.Batch(cmd, m.schedulePoll()), false
 		case ErrorMsg:
+			if msg.queryID != m.queryID {
+				return m, nil, false
+			}
 			m.err = msg.Err
+			if m.ctx != nil && errors.Is(m.ctx.Err(), context.DeadlineExceeded) {
+				m.err = fmt.Errorf("query timed out after %v: %w", m.Timeout, msg.Err)
+			}
+			m.done()
 			m.state = vsInput
 			cmd = textinput.Blink
 		}
@@ -148,6 +303,45 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd, bool) {
 			if key.Matches(msg, m.list.KeyMap.Quit) && !m.list.IsFiltered() {
 				return m, nil, true
 			}
+			if key.Matches(msg, m.KeyMap.Poll) && m.PollInterval > 0 && !m.list.SettingFilter() {
+				m.paused = !m.paused
+				if m.paused {
+					m.done()
+					return m, nil, false
+				}
+				return m, m.poll(), false
+			}
+			if m.table != nil && key.Matches(msg, m.KeyMap.Sort) && !m.list.SettingFilter() {
+				return m, m.sort(msg.String()), false
+			}
+		case pollMsg:
+			if msg.queryID != m.queryID || m.paused {
+				return m, nil, false
+			}
+			return m, m.poll(), false
+		case ResultMsg:
+			if msg.queryID != m.queryID {
+				return m, nil, false
+			}
+			m.done()
+			m.err = nil
+			return m, tea.Batch(m.refreshItems(msg.Items), m.schedulePoll()), false
+		case ErrorMsg:
+			// a poll cancelled by pausing fails too
+			if msg.queryID != m.queryID || m.paused {
+				return m, nil, false
+			}
+			// the previous results stay shown until a poll succeeds
+			m.done()
+			m.err = msg.Err
+			return m, m.schedulePoll(), false
+		case list.FilterMatchesMsg:
+			m.list, cmd = m.list.Update(msg)
+			if m.reselect != "" {
+				m.selectKey(m.reselect)
+				m.reselect = ""
+			}
+			return m, cmd, false
 		}
 		m.list, cmd = m.list.Update(msg)
 		return m, cmd, false
@@ -156,6 +350,230 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd, bool) {
 	}
 }
 
+// Typing reports whether an input currently has focus
+func (m Model) Typing() bool {
+	return m.state == vsInput && m.focusIndex < len(m.inputs)
+}
+
+// Focused returns the index of the focused input, or -1 if no input has focus
+func (m Model) Focused() int {
+	if !m.Typing() {
+		return -1
+	}
+	return m.focusIndex
+}
+
+// InputValue returns the value of input i
+func (m Model) InputValue(i int) string {
+	return m.inputs[i].Value()
+}
+
+// SetInputValue replaces the value of input i, e.g. with a value picked from another stage
+func (m *Model) SetInputValue(i int, v string) {
+	m.inputs[i].SetValue(v)
+	m.inputs[i].CursorEnd()
+}
+
+// Cancels reports whether msg cancels the in-flight query, so stages can keep it from their own bindings such as back
+func (m Model) Cancels(msg tea.KeyMsg) bool {
+	return m.state == vsLoading && key.Matches(msg, m.KeyMap.Cancel)
+}
+
+// Filtering reports whether the results are being filtered, in which case the list handles esc itself
+func (m Model) Filtering() bool {
+	return m.state == vsShow && m.list.FilterState() != list.Unfiltered
+}
+
+// Bindings lists the bindings active in the current state
+func (m Model) Bindings() []key.Binding {
+	switch m.state {
+	case vsInput:
+		if m.Focused() == 0 && len(m.history) > 0 {
+			return []key.Binding{m.KeyMap.NextField, m.KeyMap.PrevField, m.KeyMap.Submit, m.KeyMap.HistoryPrev, m.KeyMap.HistoryNext}
+		}
+		return []key.Binding{m.KeyMap.NextField, m.KeyMap.PrevField, m.KeyMap.Submit}
+	case vsLoading:
+		return []key.Binding{m.KeyMap.Cancel}
+	case vsShow:
+		bindings := []key.Binding{m.list.KeyMap.CursorUp, m.list.KeyMap.CursorDown, m.list.KeyMap.NextPage, m.list.KeyMap.PrevPage, m.list.KeyMap.Filter}
+		if m.table != nil {
+			bindings = append(bindings, m.KeyMap.Sort)
+		}
+		if m.PollInterval > 0 {
+			bindings = append(bindings, m.KeyMap.Poll)
+		}
+		return bindings
+	default:
+		return nil
+	}
+}
+
+// Loading reports whether a query is in flight
+func (m Model) Loading() bool {
+	return m.state == vsLoading
+}
+
+// Showing reports whether results are displayed and keys are not going to the filter input
+func (m Model) Showing() bool {
+	return m.state == vsShow && !m.list.SettingFilter()
+}
+
+// Items returns all results, including those hidden by the filter but not the removed rows still shown after a poll
+func (m Model) Items() []list.Item {
+	items := make([]list.Item, 0, len(m.list.Items()))
+	for _, item := range m.list.Items() {
+		if !m.diff.removed(item) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// VisibleItems returns the results matching the filter, not including the removed rows still shown after a poll
+func (m Model) VisibleItems() []list.Item {
+	items := make([]list.Item, 0, len(m.list.VisibleItems()))
+	for _, item := range m.list.VisibleItems() {
+		if !m.diff.removed(item) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Filtered reports whether a filter hides some of the results
+func (m Model) Filtered() bool {
+	return m.list.FilterState() != list.Unfiltered
+}
+
+// SelectedItem returns the highlighted result, or nil if there is none or it was removed by the last poll
+func (m Model) SelectedItem() list.Item {
+	item := m.list.SelectedItem()
+	if m.diff.removed(item) {
+		return nil
+	}
+	return item
+}
+
+// SetItems replaces the results, keeping the current filter and sort order
+func (m *Model) SetItems(items []list.Item) tea.Cmd {
+	return m.setItems(items)
+}
+
+func (m *Model) setItems(items []list.Item) tea.Cmd {
+	if m.table != nil {
+		m.table.sort(items)
+	}
+	return m.list.SetItems(items)
+}
+
+// Refresh re-runs the query with the current input values
+func (m *Model) Refresh() tea.Cmd {
+	m.err = nil
+	return m.run()
+}
+
+// Cancel abandons the in-flight query or poll, if any, returning to the inputs from a query
+func (m *Model) Cancel() {
+	m.done()
+	if m.state == vsLoading {
+		m.state = vsInput
+	}
+}
+
+// run starts the query in the background, bounded by Timeout. Results of any earlier query are ignored from then on.
+func (m *Model) run() tea.Cmd {
+	m.state = vsLoading
+	return tea.Batch(m.spinner.Tick, m.start())
+}
+
+// poll re-runs the query of the shown results, which stay shown until it completes
+func (m *Model) poll() tea.Cmd {
+	return m.start()
+}
+
+// schedulePoll refreshes the shown results after PollInterval, unless polling is disabled or paused
+func (m *Model) schedulePoll() tea.Cmd {
+	if m.PollInterval <= 0 || m.paused {
+		return nil
+	}
+	queryID := m.queryID
+	return tea.Tick(m.PollInterval, func(time.Time) tea.Msg {
+		return pollMsg{queryID: queryID}
+	})
+}
+
+// refreshItems replaces the shown results with those of a poll, marking the changed rows and keeping the cursor on
+// the same row
+func (m *Model) refreshItems(items []list.Item) tea.Cmd {
+	merged := m.diff.compare(m.Items(), items)
+	m.polled = time.Now()
+	return m.replaceItems(merged)
+}
+
+// sort sorts the table by the column of the pressed key, keeping the cursor on the same row
+func (m *Model) sort(pressed string) tea.Cmd {
+	for i, k := range m.KeyMap.Sort.Keys() {
+		if k == pressed && i < len(m.table.columns) {
+			m.table.sortBy(i)
+			return m.replaceItems(append([]list.Item(nil), m.list.Items()...))
+		}
+	}
+	return nil
+}
+
+// replaceItems shows items in place of the current results, keeping the cursor on the same row
+func (m *Model) replaceItems(items []list.Item) tea.Cmd {
+	selected := m.list.SelectedItem()
+	cmd := m.setItems(items)
+	if selected == nil {
+		return cmd
+	}
+	if m.list.FilterState() != list.Unfiltered {
+		// the filtered rows are only known once the filter command has run
+		m.reselect = itemKey(selected)
+		return cmd
+	}
+	m.selectKey(itemKey(selected))
+	return cmd
+}
+
+func (m *Model) selectKey(key string) {
+	for i, item := range m.list.VisibleItems() {
+		if itemKey(item) == key {
+			m.list.Select(i)
+			return
+		}
+	}
+}
+
+// start runs the query with the current input values in the background
+func (m *Model) start() tea.Cmd {
+	m.done()
+	if m.Timeout > 0 {
+		m.ctx, m.cancel = context.WithTimeout(context.Background(), m.Timeout)
+	} else {
+		m.ctx, m.cancel = context.WithCancel(context.Background())
+	}
+	m.queryID = atomic.AddInt64(&lastQueryID, 1)
+
+	query, ctx, queryID, values := m.query, m.ctx, m.queryID, m.inputValues()
+	return func() tea.Msg {
+		items, err := query(ctx, values)
+		if err != nil {
+			return ErrorMsg{Err: err, queryID: queryID}
+		}
+		return ResultMsg{Items: items, queryID: queryID}
+	}
+}
+
+// done releases the in-flight query's context
+func (m *Model) done() {
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+}
+
 func (m Model) validateInputs() error {
 	for _, input := range m.inputs {
 		if input.Err != nil {
@@ -165,6 +583,81 @@ func (m Model) validateInputs() error {
 	return nil
 }
 
+// recalls reports whether msg recalls a query: only the first input recalls, so the keys move between the other
+// fields, and HistoryNext moves to the next field unless a query is recalled
+func (m Model) recalls(msg tea.KeyMsg) bool {
+	if m.Focused() != 0 || len(m.history) == 0 {
+		return false
+	}
+	return key.Matches(msg, m.KeyMap.HistoryPrev) || (m.recalled >= 0 && key.Matches(msg, m.KeyMap.HistoryNext))
+}
+
+// recall shows the previous (older) or next query of the history in the inputs, returning to the draft past the latest
+func (m *Model) recall(previous bool) {
+	if previous {
+		if m.recalled+1 >= len(m.history) {
+			return
+		}
+		if m.recalled == -1 {
+			m.draft = m.inputValues()
+		}
+		m.recalled++
+		m.setInputValues(m.history[m.recalled])
+		return
+	}
+
+	if m.recalled == -1 {
+		return
+	}
+	m.recalled--
+	if m.recalled == -1 {
+		m.setInputValues(m.draft)
+		return
+	}
+	m.setInputValues(m.history[m.recalled])
+}
+
+// remember adds the submitted values to the top of the history, moving them there if already in it
+func (m *Model) remember() {
+	values := m.inputValues()
+	history := make([][]string, 0, len(m.history)+1)
+	history = append(history, values)
+	for _, entry := range m.history {
+		if !sameValues(entry, values) && len(history) < maxHistory {
+			history = append(history, entry)
+		}
+	}
+	m.history = history
+	m.recalled = -1
+	m.draft = nil
+
+	if m.record != nil {
+		m.record(history)
+	}
+}
+
+func sameValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Model) setInputValues(values []string) {
+	for i := range m.inputs {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		m.SetInputValue(i, value)
+	}
+}
+
 func (m Model) inputValues() []string {
 	s := make([]string, 0, len(m.inputs))
 	for _, input := range m.inputs {
@@ -173,8 +666,8 @@ func (m Model) inputValues() []string {
 	return s
 }
 
-func (m *Model) updateCursor(k tea.KeyType) {
-	if k == tea.KeyUp || k == tea.KeyShiftTab {
+func (m *Model) updateCursor(prev bool) {
+	if prev {
 		m.focusIndex--
 	} else {
 		m.focusIndex++
@@ -223,8 +716,48 @@ func (m Model) View() string {
 			b.WriteString(m.spinner.View())
 		}
 	case vsShow:
+		b.WriteString(m.pollView())
+		if m.table != nil {
+			// the cells are indented past the change markers and the cursor
+			m.table.layout(m.list.Items(), m.list.Width()-2)
+			m.list.Title = "    " + m.table.header()
+		}
 		b.WriteString(listStyle.Render(m.list.View()))
 	}
 
 	return b.String()
 }
+
+// pollView reports when the results were refreshed and how the last poll changed them, or why it failed
+func (m Model) pollView() string {
+	var rows string
+	if m.table != nil {
+		// the list's status bar is hidden in tables
+		rows = fmt.Sprintf("%v rows", len(m.Items()))
+		if m.list.FilterState() != list.Unfiltered {
+			rows = fmt.Sprintf("%v of %v rows matching %q", len(m.list.VisibleItems()), len(m.list.Items()), m.list.FilterValue())
+		}
+	}
+	if m.PollInterval <= 0 {
+		if rows == "" {
+			return ""
+		}
+		return statusStyle.Render(rows) + "\n"
+	}
+
+	status := fmt.Sprintf("updated %v • refreshing every %v", m.polled.Format("15:04:05"), m.PollInterval)
+	if m.paused {
+		status = fmt.Sprintf("updated %v • auto-refresh paused", m.polled.Format("15:04:05"))
+	}
+	if m.diff.rows != nil {
+		status += fmt.Sprintf(" • %v added, %v changed, %v removed", m.diff.count(added), m.diff.count(changed), m.diff.count(removed))
+	}
+	if rows != "" {
+		status = rows + " • " + status
+	}
+	line := statusStyle.Render(status)
+	if m.err != nil {
+		line += " " + errorStyle.Render(m.err.Error())
+	}
+	return line + "\n"
+}
@@ -0,0 +1,275 @@
+package listquery
+
+import (
+	"context"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testRow is a result keyed by name, shown as a table row
+type testRow struct {
+	name  string
+	value string
+}
+
+func (r testRow) FilterValue() string {
+	return r.name
+}
+
+func (r testRow) Key() string {
+	return r.name
+}
+
+func (r testRow) Cells() []string {
+	return []string{r.name, r.value}
+}
+
+func newTestModel(inputs int, query queryFn) Model {
+	ts := make([]textinput.Model, inputs)
+	for i := range ts {
+		ts[i] = textinput.New()
+	}
+	m := New(ts, spinner.Dot, list.NewDefaultDelegate(), query)
+	m.PollInterval = 0
+	m.Init(80, 24)
+	return m
+}
+
+func press(m Model, keys ...tea.KeyType) Model {
+	for _, k := range keys {
+		m, _, _ = m.Update(tea.KeyMsg{Type: k})
+	}
+	return m
+}
+
+// queryMsg runs the commands of cmd, returning the query's result or error
+func queryMsg(cmd tea.Cmd) tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	switch msg := cmd().(type) {
+	case tea.BatchMsg:
+		for _, cmd := range msg {
+			if msg := queryMsg(cmd); msg != nil {
+				return msg
+			}
+		}
+	case ResultMsg, ErrorMsg:
+		return msg
+	}
+	return nil
+}
+
+// submit moves focus to the submit button and presses it, returning the query's result or error
+func submit(t *testing.T, m Model) (Model, tea.Msg) {
+	t.Helper()
+
+	var cmd tea.Cmd
+	for m.Typing() {
+		m, _, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+	m, cmd, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.Loading() {
+		t.Fatalf("query not started, err = %v", m.err)
+	}
+	return m, queryMsg(cmd)
+}
+
+func TestHistoryRecall(t *testing.T) {
+	var recorded [][]string
+	m := newTestModel(2, func(ctx context.Context, values []string) ([]list.Item, error) {
+		return nil, nil
+	})
+	m.SetHistory([][]string{{"a", "1"}, {"b", "2"}}, func(history [][]string) {
+		recorded = history
+	})
+	m.SetInputValue(0, "draft")
+
+	steps := []struct {
+		key     tea.KeyType
+		values  []string
+		focused int
+	}{
+		{key: tea.KeyUp, values: []string{"a", "1"}},
+		{key: tea.KeyUp, values: []string{"b", "2"}},
+		{key: tea.KeyUp, values: []string{"b", "2"}},
+		{key: tea.KeyDown, values: []string{"a", "1"}},
+		{key: tea.KeyDown, values: []string{"draft", "1"}},
+		// past the draft, down moves to the next field, where up and down move between fields
+		{key: tea.KeyDown, values: []string{"draft", "1"}, focused: 1},
+		{key: tea.KeyUp, values: []string{"draft", "1"}},
+	}
+	for i, step := range steps {
+		m = press(m, step.key)
+		if values := m.inputValues(); !reflect.DeepEqual(values, step.values) || m.Focused() != step.focused {
+			t.Fatalf("step %v: values = %v, focused = %v; want %v, %v", i, values, m.Focused(), step.values, step.focused)
+		}
+	}
+
+	m, _ = submit(t, m)
+	if want := [][]string{{"draft", "1"}, {"a", "1"}, {"b", "2"}}; !reflect.DeepEqual(recorded, want) {
+		t.Fatalf("recorded %v, want %v", recorded, want)
+	}
+
+	// resubmitting a query moves it to the top rather than repeating it
+	m.Cancel()
+	m.Init(80, 24)
+	m = press(m, tea.KeyUp, tea.KeyUp, tea.KeyUp)
+	m, _ = submit(t, m)
+	if want := [][]string{{"b", "2"}, {"draft", "1"}, {"a", "1"}}; !reflect.DeepEqual(recorded, want) {
+		t.Errorf("recorded %v, want %v", recorded, want)
+	}
+}
+
+func TestHistoryWithoutEntries(t *testing.T) {
+	m := newTestModel(2, nil)
+
+	m = press(m, tea.KeyDown)
+	if m.Focused() != 1 {
+		t.Errorf("focused = %v, want down to move to the next field without history", m.Focused())
+	}
+}
+
+func TestCancelWhileLoading(t *testing.T) {
+	cancelled := make(chan struct{})
+	m := newTestModel(1, func(ctx context.Context, values []string) ([]list.Item, error) {
+		<-ctx.Done()
+		close(cancelled)
+		return nil, ctx.Err()
+	})
+
+	var cmd tea.Cmd
+	m = press(m, tea.KeyEnter)
+	m, cmd, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.Loading() || !m.Cancels(tea.KeyMsg{Type: tea.KeyEsc}) {
+		t.Fatal("query not started")
+	}
+	result := make(chan tea.Msg)
+	go func() {
+		result <- queryMsg(cmd)
+	}()
+
+	m = press(m, tea.KeyEsc)
+	if m.state != vsInput {
+		t.Error("esc did not return to the inputs")
+	}
+	<-cancelled
+
+	// the cancelled query's error is ignored
+	m, _, _ = m.Update(<-result)
+	if m.err != nil {
+		t.Errorf("err = %v after cancelling", m.err)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	m := newTestModel(1, func(ctx context.Context, values []string) ([]list.Item, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	m.Timeout = 50 * time.Millisecond
+
+	m, msg := submit(t, m)
+	m, _, _ = m.Update(msg)
+	if m.state != vsInput {
+		t.Error("did not return to the inputs after timing out")
+	}
+	if m.err == nil || !strings.Contains(m.err.Error(), "timed out after 50ms") {
+		t.Errorf("err = %v, want the timeout reported", m.err)
+	}
+}
+
+func TestOvertakenResultIgnored(t *testing.T) {
+	var queries int
+	m := newTestModel(1, func(ctx context.Context, values []string) ([]list.Item, error) {
+		queries++
+		return []list.Item{testRow{name: strings.Repeat("x", queries)}}, nil
+	})
+
+	m, stale := submit(t, m)
+	m.Cancel()
+	m, latest := submit(t, m)
+
+	m, _, _ = m.Update(stale)
+	if !m.Loading() {
+		t.Fatal("accepted the result of an overtaken query")
+	}
+	m, _, _ = m.Update(latest)
+	if items := m.Items(); len(items) != 1 || items[0].(testRow).name != "xx" {
+		t.Errorf("items = %v, want the latest query's result", items)
+	}
+}
+
+func TestPollMarksChanges(t *testing.T) {
+	results := [][]list.Item{
+		{testRow{"a", "1"}, testRow{"b", "2"}, testRow{"c", "3"}},
+		{testRow{"a", "1"}, testRow{"b", "20"}, testRow{"d", "4"}},
+	}
+	m := newTestModel(1, func(ctx context.Context, values []string) ([]list.Item, error) {
+		items := results[0]
+		results = results[1:]
+		return items, nil
+	})
+	m.SetColumns(Column{Title: "NAME"}, Column{Title: "VALUE", Numeric: true})
+
+	m, msg := submit(t, m)
+	m, _, _ = m.Update(msg)
+	if !m.Showing() {
+		t.Fatal("results not shown")
+	}
+
+	m.PollInterval = DefaultPollInterval
+	m, cmd, _ := m.Update(pollMsg{queryID: m.queryID})
+	m, _, _ = m.Update(queryMsg(cmd))
+
+	want := map[string]change{"b": changed, "c": removed, "d": added}
+	if !reflect.DeepEqual(m.diff.rows, want) {
+		t.Errorf("changes = %v, want %v", m.diff.rows, want)
+	}
+	if n := len(m.Items()); n != 3 {
+		t.Errorf("%v items, want the removed row left out", n)
+	}
+	if status := m.pollView(); !strings.Contains(status, "1 added, 1 changed, 1 removed") {
+		t.Errorf("status = %q, want the changes counted", status)
+	}
+}
+
+func TestSortColumns(t *testing.T) {
+	m := newTestModel(1, func(ctx context.Context, values []string) ([]list.Item, error) {
+		return []list.Item{testRow{"b", "1,024"}, testRow{"a", "n/a"}, testRow{"c", "$12.50"}}, nil
+	})
+	m.SetColumns(Column{Title: "NAME"}, Column{Title: "VALUE", Numeric: true})
+
+	m, msg := submit(t, m)
+	m, _, _ = m.Update(msg)
+
+	names := func() string {
+		var s []string
+		for _, item := range m.Items() {
+			s = append(s, item.(testRow).name)
+		}
+		return strings.Join(s, " ")
+	}
+	tests := []struct {
+		key  string
+		want string
+	}{
+		// numeric columns sort largest first, with cells that are not numbers last
+		{key: "2", want: "b c a"},
+		{key: "2", want: "c b a"},
+		{key: "1", want: "a b c"},
+		{key: "1", want: "c b a"},
+	}
+	for _, test := range tests {
+		m, _, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(test.key)})
+		if got := names(); got != test.want {
+			t.Errorf("after %v: order = %q, want %q", test.key, got, test.want)
+		}
+	}
+}
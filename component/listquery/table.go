@@ -0,0 +1,199 @@
+package listquery
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	headerStyle   = lipgloss.NewStyle().Bold(true)
+	selectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("170"))
+)
+
+const (
+	columnGap      = 2
+	minColumnWidth = 3
+)
+
+// Column describes a column of results shown as a table
+type Column struct {
+	Title string
+
+	// Numeric columns are right aligned and sorted by value, largest first
+	Numeric bool
+}
+
+// Row is implemented by items shown as a table, with one cell per column
+type Row interface {
+	Cells() []string
+}
+
+// table is the column layout and sort order of the results. It is shared with the delegate rendering the rows.
+type table struct {
+	columns []Column
+	widths  []int
+
+	// sortColumn is -1 to keep the query's order
+	sortColumn int
+	descending bool
+}
+
+func cells(item list.Item, n int) []string {
+	var c []string
+	if row, ok := item.(Row); ok {
+		c = row.Cells()
+	} else {
+		c = []string{item.FilterValue()}
+	}
+	for len(c) < n {
+		c = append(c, "")
+	}
+	return c
+}
+
+// layout sizes the columns to their contents, shrinking the widest ones until the table fits in width
+func (t *table) layout(items []list.Item, width int) {
+	t.widths = make([]int, len(t.columns))
+	for i, column := range t.columns {
+		t.widths[i] = utf8.RuneCountInString(column.Title) + 2 // room for the sort indicator
+	}
+	for _, item := range items {
+		for i, cell := range cells(item, len(t.columns))[:len(t.columns)] {
+			if n := utf8.RuneCountInString(cell); n > t.widths[i] {
+				t.widths[i] = n
+			}
+		}
+	}
+
+	available := width - columnGap*(len(t.columns)-1)
+	for {
+		total, widest := 0, 0
+		for i, w := range t.widths {
+			total += w
+			if w > t.widths[widest] {
+				widest = i
+			}
+		}
+		if total <= available || t.widths[widest] <= minColumnWidth {
+			return
+		}
+		t.widths[widest]--
+	}
+}
+
+func (t *table) line(values []string) string {
+	parts := make([]string, len(t.columns))
+	for i, column := range t.columns {
+		value := truncate(values[i], t.widths[i])
+		if column.Numeric {
+			parts[i] = fmt.Sprintf("%*s", t.widths[i], value)
+		} else {
+			parts[i] = fmt.Sprintf("%-*s", t.widths[i], value)
+		}
+	}
+	return strings.Join(parts, strings.Repeat(" ", columnGap))
+}
+
+func (t *table) header() string {
+	titles := make([]string, len(t.columns))
+	for i, column := range t.columns {
+		titles[i] = column.Title
+		if i == t.sortColumn {
+			if t.descending {
+				titles[i] += " ↓"
+			} else {
+				titles[i] += " ↑"
+			}
+		}
+	}
+	return headerStyle.Render(t.line(titles))
+}
+
+// sortBy sorts by column, reversing the order if already sorted by it
+func (t *table) sortBy(column int) {
+	if column == t.sortColumn {
+		t.descending = !t.descending
+		return
+	}
+	t.sortColumn = column
+	t.descending = t.columns[column].Numeric
+}
+
+// sort orders items by the sort column; cells that are not numbers go last in numeric columns
+func (t *table) sort(items []list.Item) {
+	if t.sortColumn < 0 || t.sortColumn >= len(t.columns) {
+		return
+	}
+	column := t.sortColumn
+	numeric := t.columns[column].Numeric
+	sort.SliceStable(items, func(i, j int) bool {
+		a := cells(items[i], len(t.columns))[column]
+		b := cells(items[j], len(t.columns))[column]
+		if !numeric {
+			if t.descending {
+				return a > b
+			}
+			return a < b
+		}
+
+		x, xOk := parseNumber(a)
+		y, yOk := parseNumber(b)
+		if xOk != yOk {
+			return xOk
+		}
+		if t.descending {
+			return x > y
+		}
+		return x < y
+	})
+}
+
+// parseNumber reads cells such as "1,024.5" or "$12.30"
+func parseNumber(s string) (float64, bool) {
+	s = strings.NewReplacer("$", "", ",", "").Replace(strings.TrimSpace(s))
+	f, err := strconv.ParseFloat(s, 64)
+	return f, err == nil
+}
+
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string([]rune(s)[:width])
+	}
+	return string([]rune(s)[:width-1]) + "…"
+}
+
+// tableDelegate renders each result as a line of aligned cells
+type tableDelegate struct {
+	table *table
+}
+
+func (d tableDelegate) Height() int {
+	return 1
+}
+
+func (d tableDelegate) Spacing() int {
+	return 0
+}
+
+func (d tableDelegate) Update(tea.Msg, *list.Model) tea.Cmd {
+	return nil
+}
+
+func (d tableDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	line := d.table.line(cells(item, len(d.table.columns)))
+	if index == m.Index() {
+		fmt.Fprint(w, selectedStyle.Render("│ "+line))
+		return
+	}
+	fmt.Fprint(w, "  "+line)
+}
@@ -1,6 +1,9 @@
 package flags
 
-import "github.com/urfave/cli/v2"
+import (
+	"github.com/urfave/cli/v2"
+	"time"
+)
 
 var (
 	LogFile = &cli.StringFlag{
@@ -11,4 +14,40 @@ var (
 		Name:  "config",
 		Value: "settings.json",
 	}
+
+	// headless command flags
+	Output = &cli.StringFlag{
+		Name:    "output",
+		Aliases: []string{"o"},
+		Usage:   "output format: table, json or ndjson",
+		Value:   "table",
+	}
+	Profile = &cli.StringFlag{
+		Name:  "profile",
+		Usage: "profile to use instead of the config file's active profile",
+	}
+	Passphrase = &cli.StringFlag{
+		Name:    "passphrase",
+		Usage:   "passphrase for an encrypted private key (prefer the environment variable)",
+		EnvVars: []string{"TRADER_PASSPHRASE"},
+	}
+	Timeout = &cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "timeout for API requests",
+		Value: 15 * time.Second,
+	}
+	Market = &cli.StringFlag{
+		Name:     "market",
+		Usage:    "market name (e.g. SOL/USDC) or address",
+		Required: true,
+	}
+	Depth = &cli.UintFlag{
+		Name:  "depth",
+		Usage: "number of price levels per side",
+		Value: 10,
+	}
+	Owner = &cli.StringFlag{
+		Name:  "owner",
+		Usage: "owner address (defaults to the profile's public key)",
+	}
 )
@@ -0,0 +1,83 @@
+package headless
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/urfave/cli/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net"
+)
+
+// exit codes let scripts tell failures that need a config fix apart from ones worth retrying
+const (
+	exitFailure = 1
+	exitConfig  = 2
+	exitAuth    = 3
+	exitNetwork = 4
+	exitConnect = 5
+)
+
+type configError struct {
+	err error
+}
+
+func (e configError) Error() string {
+	return e.err.Error()
+}
+
+func (e configError) Unwrap() error {
+	return e.err
+}
+
+func configErrorf(format string, a ...interface{}) error {
+	return configError{err: fmt.Errorf(format, a...)}
+}
+
+// connectError is a failure to set up the API client, as opposed to a request failing once connected
+type connectError struct {
+	err error
+}
+
+func (e connectError) Error() string {
+	return e.err.Error()
+}
+
+func (e connectError) Unwrap() error {
+	return e.err
+}
+
+// exit converts err into a cli.ExitCoder with a code describing the failure class
+func exit(err error) error {
+	if err == nil {
+		return nil
+	}
+	return cli.Exit(err.Error(), exitCode(err))
+}
+
+func exitCode(err error) int {
+	var ce configError
+	if errors.As(err, &ce) {
+		return exitConfig
+	}
+	var cne connectError
+	if errors.As(err, &cne) {
+		return exitConnect
+	}
+
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.Unauthenticated, codes.PermissionDenied:
+			return exitAuth
+		case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+			return exitNetwork
+		}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) {
+		return exitNetwork
+	}
+	return exitFailure
+}
@@ -0,0 +1,31 @@
+package headless
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{errors.New("market not found"), exitFailure},
+		{errors.New("unauthorized market"), exitFailure},
+		{configErrorf("profile %v has no auth header", "default"), exitConfig},
+		{connectError{err: errors.New("dial tcp: connection refused")}, exitConnect},
+		{status.Error(codes.Unauthenticated, "invalid auth header"), exitAuth},
+		{status.Error(codes.PermissionDenied, "forbidden"), exitAuth},
+		{status.Error(codes.Unavailable, "connection reset"), exitNetwork},
+		{fmt.Errorf("open orders: %w", context.DeadlineExceeded), exitNetwork},
+	}
+	for _, test := range tests {
+		if code := exitCode(test.err); code != test.code {
+			t.Errorf("exitCode(%v) = %v, want %v", test.err, code, test.code)
+		}
+	}
+}
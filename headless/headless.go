@@ -0,0 +1,247 @@
+package headless
+
+import (
+	"context"
+	"fmt"
+	"github.com/aspin/solana-trader-tui/flags"
+	"github.com/aspin/solana-trader-tui/store"
+	"github.com/urfave/cli/v2"
+	"sort"
+	"strconv"
+)
+
+var commonFlags = []cli.Flag{
+	flags.Output,
+	flags.Profile,
+	flags.Passphrase,
+	flags.Timeout,
+}
+
+// Commands are the non-interactive equivalents of the TUI stages, for scripts and cron jobs
+func Commands() []*cli.Command {
+	return []*cli.Command{
+		{
+			Name:   "open-orders",
+			Usage:  "List open orders in a market for the profile's open orders address",
+			Flags:  append([]cli.Flag{flags.Market}, commonFlags...),
+			Action: action(openOrders),
+		},
+		{
+			Name:   "orderbook",
+			Usage:  "Print the bids and asks of a market",
+			Flags:  append([]cli.Flag{flags.Market, flags.Depth}, commonFlags...),
+			Action: action(orderbook),
+		},
+		{
+			Name:   "markets",
+			Usage:  "List available markets",
+			Flags:  commonFlags,
+			Action: action(markets),
+		},
+		{
+			Name:   "balance",
+			Usage:  "Print token balances of an owner",
+			Flags:  append([]cli.Flag{flags.Owner}, commonFlags...),
+			Action: action(balance),
+		},
+	}
+}
+
+type commandFn func(ctx context.Context, c *cli.Context, appStore *store.App) (result, error)
+
+// action loads and connects the store, runs fn with a timeout and writes its result in the requested format
+func action(fn commandFn) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		format := c.String(flags.Output.Name)
+		if err := validateFormat(format); err != nil {
+			return exit(err)
+		}
+
+		appStore, err := connect(c)
+		if err != nil {
+			return exit(err)
+		}
+
+		ctx, cancel := context.WithTimeout(c.Context, c.Duration(flags.Timeout.Name))
+		defer cancel()
+
+		r, err := fn(ctx, c, appStore)
+		if err != nil {
+			return exit(err)
+		}
+		return exit(r.write(c.App.Writer, format))
+	}
+}
+
+func connect(c *cli.Context) (*store.App, error) {
+	appStore, err := store.LoadFromFile(c.String(flags.ConfigFile.Name))
+	if err != nil {
+		return nil, configError{err: err}
+	}
+
+	if profile := c.String(flags.Profile.Name); profile != "" {
+		if err = appStore.SwitchProfile(profile); err != nil {
+			return nil, configError{err: err}
+		}
+	}
+
+	if appStore.NeedsUnlock() {
+		passphrase := c.String(flags.Passphrase.Name)
+		if passphrase == "" {
+			return nil, configErrorf("private key of profile %v is encrypted: set %v", appStore.Profile, flags.Passphrase.EnvVars[0])
+		}
+		if err = appStore.Unlock(passphrase); err != nil {
+			return nil, configErrorf("could not unlock private key: %w", err)
+		}
+	}
+
+	if appStore.NeedsInit() {
+		return nil, configErrorf("profile %v has no auth header: configure it from the settings stage", appStore.Profile)
+	}
+
+	if err = appStore.Connect(); err != nil {
+		return nil, connectError{err: fmt.Errorf("could not connect API client: %w", err)}
+	}
+	return appStore, nil
+}
+
+type openOrderRecord struct {
+	OrderID       string   `json:"orderID"`
+	ClientOrderID string   `json:"clientOrderID"`
+	Market        string   `json:"market"`
+	Side          string   `json:"side"`
+	Types         []string `json:"types"`
+	Price         float64  `json:"price"`
+	RemainingSize float64  `json:"remainingSize"`
+}
+
+func openOrders(ctx context.Context, c *cli.Context, appStore *store.App) (result, error) {
+	market := c.String(flags.Market.Name)
+	response, err := appStore.Provider.GetOpenOrders(ctx, market, "", appStore.Settings.OpenOrdersAddress.String(), appStore.Settings.Project)
+	if err != nil {
+		return result{}, err
+	}
+
+	r := result{columns: []string{"ORDER ID", "CLIENT ORDER ID", "SIDE", "TYPES", "PRICE", "REMAINING SIZE"}}
+	for _, order := range response.Orders {
+		types := make([]string, 0, len(order.Types))
+		for _, t := range order.Types {
+			types = append(types, t.String())
+		}
+
+		record := openOrderRecord{
+			OrderID:       order.OrderID,
+			ClientOrderID: order.ClientOrderID,
+			Market:        market,
+			Side:          order.Side.String(),
+			Types:         types,
+			Price:         order.Price,
+			RemainingSize: order.RemainingSize,
+		}
+		r.add(record, record.OrderID, record.ClientOrderID, record.Side, fmt.Sprint(types), formatFloat(record.Price), formatFloat(record.RemainingSize))
+	}
+	return r, nil
+}
+
+type orderbookRecord struct {
+	Market string  `json:"market"`
+	Side   string  `json:"side"`
+	Level  int     `json:"level"`
+	Price  float64 `json:"price"`
+	Size   float64 `json:"size"`
+}
+
+func orderbook(ctx context.Context, c *cli.Context, appStore *store.App) (result, error) {
+	response, err := appStore.Provider.GetOrderbook(ctx, c.String(flags.Market.Name), uint32(c.Uint(flags.Depth.Name)), appStore.Settings.Project)
+	if err != nil {
+		return result{}, err
+	}
+
+	r := result{columns: []string{"SIDE", "LEVEL", "PRICE", "SIZE"}}
+	for i, bid := range response.Bids {
+		record := orderbookRecord{Market: response.Market, Side: "bid", Level: i, Price: bid.Price, Size: bid.Size}
+		r.add(record, record.Side, strconv.Itoa(i), formatFloat(record.Price), formatFloat(record.Size))
+	}
+	for i, ask := range response.Asks {
+		record := orderbookRecord{Market: response.Market, Side: "ask", Level: i, Price: ask.Price, Size: ask.Size}
+		r.add(record, record.Side, strconv.Itoa(i), formatFloat(record.Price), formatFloat(record.Size))
+	}
+	return r, nil
+}
+
+type marketRecord struct {
+	Market     string `json:"market"`
+	Address    string `json:"address"`
+	Status     string `json:"status"`
+	BaseMint   string `json:"baseMint"`
+	QuotedMint string `json:"quotedMint"`
+	Project    string `json:"project"`
+}
+
+func markets(ctx context.Context, c *cli.Context, appStore *store.App) (result, error) {
+	response, err := appStore.Provider.GetMarkets(ctx)
+	if err != nil {
+		return result{}, err
+	}
+
+	names := make([]string, 0, len(response.Markets))
+	for name := range response.Markets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	r := result{columns: []string{"MARKET", "ADDRESS", "STATUS", "BASE MINT", "QUOTE MINT", "PROJECT"}}
+	for _, name := range names {
+		market := response.Markets[name]
+		record := marketRecord{
+			Market:     name,
+			Address:    market.Address,
+			Status:     market.Status.String(),
+			BaseMint:   market.BaseMint,
+			QuotedMint: market.QuotedMint,
+			Project:    market.Project.String(),
+		}
+		r.add(record, record.Market, record.Address, record.Status, record.BaseMint, record.QuotedMint, record.Project)
+	}
+	return r, nil
+}
+
+type balanceRecord struct {
+	Symbol           string  `json:"symbol"`
+	Address          string  `json:"address"`
+	WalletAmount     float64 `json:"walletAmount"`
+	UnsettledAmount  float64 `json:"unsettledAmount"`
+	OpenOrdersAmount float64 `json:"openOrdersAmount"`
+}
+
+func balance(ctx context.Context, c *cli.Context, appStore *store.App) (result, error) {
+	owner := c.String(flags.Owner.Name)
+	if owner == "" {
+		if appStore.Settings.PublicKey.IsZero() {
+			return result{}, configErrorf("no --%v given and profile %v has no public key", flags.Owner.Name, appStore.Profile)
+		}
+		owner = appStore.Settings.PublicKey.String()
+	}
+
+	response, err := appStore.Provider.GetAccountBalance(ctx, owner)
+	if err != nil {
+		return result{}, err
+	}
+
+	r := result{columns: []string{"SYMBOL", "ADDRESS", "WALLET", "UNSETTLED", "OPEN ORDERS"}}
+	for _, token := range response.Tokens {
+		record := balanceRecord{
+			Symbol:           token.Symbol,
+			Address:          token.Address,
+			WalletAmount:     token.WalletAmount,
+			UnsettledAmount:  token.UnsettledAmount,
+			OpenOrdersAmount: token.OpenOrdersAmount,
+		}
+		r.add(record, record.Symbol, record.Address, formatFloat(record.WalletAmount), formatFloat(record.UnsettledAmount), formatFloat(record.OpenOrdersAmount))
+	}
+	return r, nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,70 @@
+package headless
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	formatTable  = "table"
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+)
+
+// result is a command's output: rows for table output, and one record per row for json and ndjson output
+type result struct {
+	columns []string
+	rows    [][]string
+	records []interface{}
+}
+
+func (r *result) add(record interface{}, row ...string) {
+	r.records = append(r.records, record)
+	r.rows = append(r.rows, row)
+}
+
+func validateFormat(format string) error {
+	switch format {
+	case formatTable, formatJSON, formatNDJSON:
+		return nil
+	default:
+		return configErrorf("unknown output format: %v", format)
+	}
+}
+
+func (r result) write(w io.Writer, format string) error {
+	switch format {
+	case formatTable:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		if _, err := fmt.Fprintln(tw, strings.Join(r.columns, "\t")); err != nil {
+			return err
+		}
+		for _, row := range r.rows {
+			if _, err := fmt.Fprintln(tw, strings.Join(row, "\t")); err != nil {
+				return err
+			}
+		}
+		return tw.Flush()
+	case formatJSON:
+		records := r.records
+		if records == nil {
+			records = []interface{}{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	case formatNDJSON:
+		enc := json.NewEncoder(w)
+		for _, record := range r.records {
+			if err := enc.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return configErrorf("unknown output format: %v", format)
+	}
+}
@@ -0,0 +1,171 @@
+package keymap
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"sort"
+	"strings"
+)
+
+// KeyMap holds every global and stage binding so they can be overridden from the config file and listed in the help overlay
+type KeyMap struct {
+	ForceQuit key.Binding
+	Quit      key.Binding
+	Back      key.Binding
+	Cancel    key.Binding
+	Confirm   key.Binding
+	Help      key.Binding
+
+	Select    key.Binding
+	Submit    key.Binding
+	NextField key.Binding
+	PrevField key.Binding
+
+	NewProfile key.Binding
+	Refresh    key.Binding
+	Edit       key.Binding
+
+	Mark             key.Binding
+	CancelOrder      key.Binding
+	CancelByClientID key.Binding
+	CancelAll        key.Binding
+	Amend            key.Binding
+	Settle           key.Binding
+	Sort             key.Binding
+
+	Favorite      key.Binding
+	PickMarket    key.Binding
+	FavoritesOnly key.Binding
+
+	ScrollUp   key.Binding
+	ScrollDown key.Binding
+	Pause      key.Binding
+
+	HistoryPrev key.Binding
+	HistoryNext key.Binding
+}
+
+func Default() KeyMap {
+	return KeyMap{
+		ForceQuit: key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit")),
+		Quit:      key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
+		Back:      key.NewBinding(key.WithKeys("esc", "backspace"), key.WithHelp("esc/backspace", "back")),
+		Cancel:    key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel")),
+		Confirm:   key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "confirm")),
+		Help:      key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "toggle help")),
+
+		Select:    key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select")),
+		Submit:    key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "next field / submit")),
+		NextField: key.NewBinding(key.WithKeys("tab", "down"), key.WithHelp("tab/↓", "next field")),
+		PrevField: key.NewBinding(key.WithKeys("shift+tab", "up"), key.WithHelp("shift+tab/↑", "previous field")),
+
+		NewProfile: key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "new profile")),
+		Refresh:    key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "refresh")),
+		Edit:       key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit query")),
+
+		Mark:             key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "mark")),
+		CancelOrder:      key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "cancel order(s)")),
+		CancelByClientID: key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "cancel by client order ID")),
+		CancelAll:        key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "cancel all in market")),
+		Amend:            key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "amend order")),
+		Settle:           key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "settle funds")),
+		Sort:             key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"), key.WithHelp("1-9", "sort by column")),
+
+		Favorite:      key.NewBinding(key.WithKeys("*"), key.WithHelp("*", "star market")),
+		PickMarket:    key.NewBinding(key.WithKeys("ctrl+p"), key.WithHelp("ctrl+p", "pick market")),
+		FavoritesOnly: key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "toggle favorites only")),
+
+		ScrollUp:   key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "scroll up")),
+		ScrollDown: key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "scroll down")),
+		Pause:      key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "pause/resume")),
+
+		HistoryPrev: key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "previous query")),
+		HistoryNext: key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "next query")),
+	}
+}
+
+// New returns the default keymap with overrides applied. Overrides are keyed by binding name (e.g. "quit") and replace all of the binding's keys.
+// Invalid overrides are skipped, keeping the binding's default, and reported in the error along with the keymap.
+func New(overrides map[string][]string) (KeyMap, error) {
+	k := Default()
+	bindings := k.named()
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var invalid []string
+	for _, name := range names {
+		keys := overrides[name]
+		b, ok := bindings[name]
+		if !ok {
+			invalid = append(invalid, fmt.Sprintf("unknown key binding %v", name))
+			continue
+		}
+		if len(keys) == 0 {
+			invalid = append(invalid, fmt.Sprintf("key binding %v has no keys", name))
+			continue
+		}
+
+		b.SetKeys(keys...)
+		b.SetHelp(strings.Join(keys, "/"), b.Help().Desc)
+	}
+	if len(invalid) > 0 {
+		return k, fmt.Errorf("%v (available: %v)", strings.Join(invalid, "; "), strings.Join(k.Names(), ", "))
+	}
+	return k, nil
+}
+
+// Global lists the bindings available from every stage
+func (k KeyMap) Global() []key.Binding {
+	return []key.Binding{k.Back, k.Quit, k.ForceQuit, k.Help}
+}
+
+// Names lists the binding names accepted by New
+func (k *KeyMap) Names() []string {
+	names := make([]string, 0)
+	for name := range k.named() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (k *KeyMap) named() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"forceQuit":  &k.ForceQuit,
+		"quit":       &k.Quit,
+		"back":       &k.Back,
+		"cancel":     &k.Cancel,
+		"confirm":    &k.Confirm,
+		"help":       &k.Help,
+		"select":     &k.Select,
+		"submit":     &k.Submit,
+		"nextField":  &k.NextField,
+		"prevField":  &k.PrevField,
+		"newProfile": &k.NewProfile,
+		"refresh":    &k.Refresh,
+		"edit":       &k.Edit,
+
+		"mark":             &k.Mark,
+		"cancelOrder":      &k.CancelOrder,
+		"cancelByClientID": &k.CancelByClientID,
+		"cancelAll":        &k.CancelAll,
+		"amend":            &k.Amend,
+		"settle":           &k.Settle,
+		"sort":             &k.Sort,
+
+		"favorite":      &k.Favorite,
+		"pickMarket":    &k.PickMarket,
+		"favoritesOnly": &k.FavoritesOnly,
+
+		"scrollUp":   &k.ScrollUp,
+		"scrollDown": &k.ScrollDown,
+		"pause":      &k.Pause,
+
+		"historyPrev": &k.HistoryPrev,
+		"historyNext": &k.HistoryNext,
+	}
+}
@@ -0,0 +1,35 @@
+package keymap
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewSkipsInvalidOverrides(t *testing.T) {
+	k, err := New(map[string][]string{
+		"quit":    {"x"},
+		"unknown": {"u"},
+		"help":    {},
+	})
+	if err == nil || !strings.Contains(err.Error(), "unknown key binding unknown") || !strings.Contains(err.Error(), "key binding help has no keys") {
+		t.Errorf("err = %v, want both invalid overrides reported", err)
+	}
+	if keys := k.Quit.Keys(); !reflect.DeepEqual(keys, []string{"x"}) {
+		t.Errorf("quit keys = %v, want the valid override kept", keys)
+	}
+	if keys := k.Help.Keys(); !reflect.DeepEqual(keys, Default().Help.Keys()) {
+		t.Errorf("help keys = %v, want the default", keys)
+	}
+}
+
+func TestDefaultMarkAndSelectDistinct(t *testing.T) {
+	k := Default()
+	for _, mark := range k.Mark.Keys() {
+		for _, selectKey := range k.Select.Keys() {
+			if mark == selectKey {
+				t.Errorf("%q both marks and selects", mark)
+			}
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package program
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aspin/solana-trader-tui/component/listquery"
+	"github.com/aspin/solana-trader-tui/store"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"strings"
+)
+
+type balancesModel struct {
+	appStore *store.App
+
+	listquery listquery.Model
+}
+
+func newBalancesModel(appStore *store.App) StageModel {
+	ownerInput := textinput.New()
+	ownerInput.Placeholder = "Owner Address (default public key)"
+	ownerInput.Focus()
+	ownerInput.PromptStyle = focusedStyle
+
+	lq := listquery.New([]textinput.Model{ownerInput}, spinner.Points, list.NewDefaultDelegate(), nil)
+	lq.KeyMap = listQueryKeyMap()
+	lq.Timeout = queryTimeout(appStore)
+	lq.PollInterval = pollInterval(appStore)
+	lq.SetColumns(balanceColumns...)
+	lq.SortBy(balanceValueColumn)
+
+	m := &balancesModel{
+		appStore:  appStore,
+		listquery: lq,
+	}
+	m.listquery.SetQuery(m.fetchBalances)
+	m.listquery.SetHistory(appStore.History["balances"], recordHistory(appStore, "balances"))
+	return m
+}
+
+func (m *balancesModel) Init(dispatch StageDispatcher) tea.Cmd {
+	return m.listquery.Init(m.appStore.UI.WindowWidth, m.appStore.UI.WindowHeight)
+}
+
+func (m *balancesModel) Update(msg tea.Msg) (Stage, StageModel, tea.Cmd) {
+	var (
+		cmd  tea.Cmd
+		exit bool
+	)
+
+	m.listquery, cmd, exit = m.listquery.Update(msg)
+	if exit {
+		return StageBack, m, nil
+	}
+	return StageBalances, m, cmd
+}
+
+func (m *balancesModel) CapturesKey(msg tea.KeyMsg) bool {
+	return m.listquery.Filtering() || m.listquery.Cancels(msg) || (m.listquery.Typing() && capturesTextKey(msg))
+}
+
+func (m *balancesModel) Bindings() []key.Binding {
+	return m.listquery.Bindings()
+}
+
+func (m *balancesModel) Leave() {
+	m.listquery.Cancel()
+}
+
+func (m *balancesModel) Busy() bool {
+	return m.listquery.Loading()
+}
+
+// fetchBalances lists the owner's token balances, valued with the price endpoint. Balances are still shown if prices cannot be fetched.
+func (m *balancesModel) fetchBalances(ctx context.Context, vs []string) ([]list.Item, error) {
+	traderProvider, err := m.appStore.Connected()
+	if err != nil {
+		return nil, err
+	}
+
+	owner := strings.TrimSpace(vs[0])
+	if owner == "" {
+		settings := m.appStore.CurrentSettings()
+		if settings.PublicKey.IsZero() {
+			return nil, errors.New("no owner given and no public key set in settings")
+		}
+		owner = settings.PublicKey.String()
+	}
+
+	balances, err := traderProvider.GetAccountBalance(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]string, 0, len(balances.Tokens))
+	for _, token := range balances.Tokens {
+		tokens = append(tokens, token.Address)
+	}
+
+	prices := make(map[string]float64)
+	var priceErr error
+	if len(tokens) > 0 {
+		response, err := traderProvider.GetPrice(ctx, tokens)
+		if err != nil {
+			priceErr = err
+		} else {
+			for _, price := range response.TokenPrices {
+				prices[price.Token] = midPrice(price.Buy, price.Sell)
+				prices[price.TokenAddress] = midPrice(price.Buy, price.Sell)
+			}
+		}
+	}
+
+	items := make([]list.Item, 0, len(balances.Tokens))
+	for _, token := range balances.Tokens {
+		item := newBalanceItem(token)
+		item.priceErr = priceErr
+		item.price, item.hasPrice = prices[token.Address]
+		if !item.hasPrice {
+			item.price, item.hasPrice = prices[token.Symbol]
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// midPrice averages the buy and sell prices, falling back to whichever is quoted
+func midPrice(buy, sell float64) float64 {
+	switch {
+	case buy > 0 && sell > 0:
+		return (buy + sell) / 2
+	case buy > 0:
+		return buy
+	default:
+		return sell
+	}
+}
+
+func (m balancesModel) View() string {
+	var b strings.Builder
+	b.WriteString(m.listquery.View())
+
+	if m.listquery.Showing() {
+		total := 0.0
+		for _, item := range m.listquery.VisibleItems() {
+			if balance, ok := item.(balanceItem); ok && balance.hasPrice {
+				total += balance.value()
+			}
+		}
+
+		// every result of a query carries the same price error
+		var priceErr error
+		for _, item := range m.listquery.Items() {
+			if balance, ok := item.(balanceItem); ok && balance.priceErr != nil {
+				priceErr = balance.priceErr
+				break
+			}
+		}
+
+		b.WriteRune('\n')
+		if m.listquery.Filtered() {
+			b.WriteString(statusStyle.Render(fmt.Sprintf("total value of matching tokens: $%.2f", total)))
+		} else {
+			b.WriteString(statusStyle.Render(fmt.Sprintf("total value: $%.2f", total)))
+		}
+		if priceErr != nil {
+			b.WriteRune('\n')
+			b.WriteString(errorStyle.Render(fmt.Sprintf("could not fetch token prices, so values are missing: %v", priceErr)))
+		}
+	}
+	return b.String()
+}
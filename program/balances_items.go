@@ -0,0 +1,68 @@
+package program
+
+import (
+	"fmt"
+	"github.com/aspin/solana-trader-tui/component/listquery"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+)
+
+// balanceValueColumn is the default sort, largest value first
+const balanceValueColumn = 6
+
+var balanceColumns = []listquery.Column{
+	{Title: "SYMBOL"},
+	{Title: "ADDRESS"},
+	{Title: "WALLET", Numeric: true},
+	{Title: "UNSETTLED", Numeric: true},
+	{Title: "OPEN ORDERS", Numeric: true},
+	{Title: "TOTAL", Numeric: true},
+	{Title: "VALUE", Numeric: true},
+}
+
+type balanceItem struct {
+	symbol           string
+	address          string
+	walletAmount     float64
+	unsettledAmount  float64
+	openOrdersAmount float64
+
+	// price is in USD; hasPrice is unset for tokens the price endpoint does not know, or if priceErr prevented fetching prices
+	price    float64
+	hasPrice bool
+	priceErr error
+}
+
+func (i balanceItem) FilterValue() string {
+	return i.symbol
+}
+
+func (i balanceItem) Cells() []string {
+	value := "-"
+	if i.hasPrice {
+		value = fmt.Sprintf("$%.2f", i.value())
+	}
+	return []string{i.symbol, i.address, formatFloat(i.walletAmount), formatFloat(i.unsettledAmount), formatFloat(i.openOrdersAmount), formatFloat(i.total()), value}
+}
+
+// Key identifies the token across polls, as symbols are not unique
+func (i balanceItem) Key() string {
+	return i.address
+}
+
+func (i balanceItem) total() float64 {
+	return i.walletAmount + i.unsettledAmount + i.openOrdersAmount
+}
+
+func (i balanceItem) value() float64 {
+	return i.total() * i.price
+}
+
+func newBalanceItem(token *pb.TokenBalance) balanceItem {
+	return balanceItem{
+		symbol:           token.Symbol,
+		address:          token.Address,
+		walletAmount:     token.WalletAmount,
+		unsettledAmount:  token.UnsettledAmount,
+		openOrdersAmount: token.OpenOrdersAmount,
+	}
+}
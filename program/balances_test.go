@@ -0,0 +1,70 @@
+package program
+
+import (
+	"context"
+	"errors"
+	"github.com/aspin/solana-trader-tui/store"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"strings"
+	"testing"
+)
+
+// failingPrices has balances but no prices
+type failingPrices struct {
+	*store.FakeProvider
+}
+
+func (p failingPrices) GetPrice(ctx context.Context, tokens []string) (*pb.GetPriceResponse, error) {
+	return nil, errors.New("price service unavailable")
+}
+
+// addBalances gives the app's owner SOL and USDC balances, priced by mint
+func addBalances(app *store.App, fake *store.FakeProvider) {
+	owner := app.Settings.PublicKey.String()
+	fake.Balances[owner] = []*pb.TokenBalance{
+		{Symbol: "SOL", Address: "sol-mint", WalletAmount: 2},
+		{Symbol: "USDC", Address: "usdc-mint", WalletAmount: 30},
+	}
+	fake.Prices["sol-mint"] = &pb.TokenPrice{Token: "SOL", TokenAddress: "sol-mint", Buy: 10, Sell: 10}
+	fake.Prices["usdc-mint"] = &pb.TokenPrice{Token: "USDC", TokenAddress: "usdc-mint", Buy: 1, Sell: 1}
+}
+
+func TestBalancesFilteredTotal(t *testing.T) {
+	fake := store.NewFakeProvider()
+	app := newTestApp(t, fake)
+	addBalances(app, fake)
+	d := newStageDriver(t, newBalancesModel(app))
+	m := d.model.(*balancesModel)
+
+	d.keys("enter", "enter")
+	d.until("the balances", func() bool {
+		return len(m.listquery.Items()) == 2
+	})
+	if view := m.View(); !strings.Contains(view, "total value: $50.00") {
+		t.Errorf("view has no total of both tokens:\n%v", view)
+	}
+
+	d.keys("/", "USDC", "enter")
+	d.until("the filter", func() bool {
+		return len(m.listquery.VisibleItems()) == 1
+	})
+	if view := m.View(); !strings.Contains(view, "total value of matching tokens: $30.00") {
+		t.Errorf("view has no total of the matching token:\n%v", view)
+	}
+}
+
+func TestBalancesPriceError(t *testing.T) {
+	fake := store.NewFakeProvider()
+	app := newTestApp(t, failingPrices{fake})
+	addBalances(app, fake)
+	d := newStageDriver(t, newBalancesModel(app))
+	m := d.model.(*balancesModel)
+
+	d.keys("enter", "enter")
+	d.until("the balances", func() bool {
+		return len(m.listquery.Items()) == 2
+	})
+	if view := m.View(); !strings.Contains(view, "price service unavailable") {
+		t.Errorf("view does not explain the missing values:\n%v", view)
+	}
+}
@@ -1,6 +1,7 @@
 package program
 
 import (
+	"fmt"
 	"github.com/aspin/solana-trader-tui/store"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -39,6 +40,6 @@ func (m errorModel) View() string {
 	b.WriteRune('\n')
 	b.WriteRune('\n')
 
-	b.WriteString(helpStyle.Render("(ctrl+c or esc to exit)"))
+	b.WriteString(helpStyle.Render(fmt.Sprintf("(%v or %v to exit)", keys.Quit.Help().Key, keys.ForceQuit.Help().Key)))
 	return b.String()
 }
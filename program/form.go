@@ -0,0 +1,76 @@
+package program
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"strings"
+)
+
+// focusInputs focuses the input at focusIndex and blurs the rest; focusIndex == len(inputs) is the submit button
+func focusInputs(inputs []textinput.Model, focusIndex int) tea.Cmd {
+	cmds := make([]tea.Cmd, len(inputs))
+	for i := range inputs {
+		if i == focusIndex {
+			cmds[i] = inputs[i].Focus()
+			inputs[i].PromptStyle = focusedStyle
+			continue
+		}
+
+		inputs[i].Blur()
+		inputs[i].PromptStyle = noStyle
+	}
+	return tea.Batch(cmds...)
+}
+
+// moveFocus advances focusIndex across count inputs plus the submit button, wrapping around
+func moveFocus(focusIndex, count int, prev bool) int {
+	if prev {
+		focusIndex--
+	} else {
+		focusIndex++
+	}
+
+	if focusIndex > count {
+		return 0
+	} else if focusIndex < 0 {
+		return count
+	}
+	return focusIndex
+}
+
+// updateForm moves focus between inputs and the submit button, passing other messages to the inputs. It reports
+// whether submit was pressed on the submit button, leaving the caller to submit the form.
+func updateForm(msg tea.Msg, inputs []textinput.Model, focusIndex *int) (tea.Cmd, bool) {
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, keys.NextField, keys.PrevField, keys.Submit) {
+		if key.Matches(msg, keys.Submit) && *focusIndex == len(inputs) {
+			return nil, true
+		}
+
+		*focusIndex = moveFocus(*focusIndex, len(inputs), key.Matches(msg, keys.PrevField))
+		return focusInputs(inputs, *focusIndex), false
+	}
+
+	cmds := make([]tea.Cmd, len(inputs))
+	for i := range inputs {
+		inputs[i], cmds[i] = inputs[i].Update(msg)
+	}
+	return tea.Batch(cmds...), false
+}
+
+func inputsView(inputs []textinput.Model, focusIndex int) string {
+	var b strings.Builder
+
+	for i := range inputs {
+		b.WriteString(inputs[i].View())
+		b.WriteRune('\n')
+	}
+
+	button := "\n[ Submit ]\n"
+	if focusIndex == len(inputs) {
+		button = focusedStyle.Render(button)
+	}
+	b.WriteString(button)
+	b.WriteRune('\n')
+	return b.String()
+}
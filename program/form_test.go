@@ -0,0 +1,35 @@
+package program
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"testing"
+)
+
+func TestUpdateForm(t *testing.T) {
+	inputs := []textinput.Model{textinput.New(), textinput.New()}
+	focusIndex := 0
+	focusInputs(inputs, focusIndex)
+
+	tests := []struct {
+		msg        tea.KeyMsg
+		focusIndex int
+		submitted  bool
+	}{
+		{msg: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")}, focusIndex: 0},
+		{msg: tea.KeyMsg{Type: tea.KeyEnter}, focusIndex: 1},
+		{msg: tea.KeyMsg{Type: tea.KeyTab}, focusIndex: 2},
+		{msg: tea.KeyMsg{Type: tea.KeyEnter}, focusIndex: 2, submitted: true},
+		{msg: tea.KeyMsg{Type: tea.KeyTab}, focusIndex: 0},
+		{msg: tea.KeyMsg{Type: tea.KeyShiftTab}, focusIndex: 2},
+	}
+	for i, test := range tests {
+		_, submitted := updateForm(test.msg, inputs, &focusIndex)
+		if focusIndex != test.focusIndex || submitted != test.submitted {
+			t.Errorf("%v: %v moved focus to %v and submitted = %v, want %v and %v", i, test.msg, focusIndex, submitted, test.focusIndex, test.submitted)
+		}
+	}
+	if v := inputs[0].Value(); v != "a" {
+		t.Errorf("first input = %q, want the typed key", v)
+	}
+}
@@ -0,0 +1,243 @@
+package program
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aspin/solana-trader-tui/store"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"sort"
+	"strings"
+)
+
+type marketsModel struct {
+	appStore *store.App
+	dispatch StageDispatcher
+
+	list    list.Model
+	spinner spinner.Model
+
+	// markets is cached across visits; refresh reloads it
+	markets map[string]*pb.Market
+	loading bool
+	picking bool
+	err     error
+}
+
+type marketsMsg struct {
+	markets map[string]*pb.Market
+	err     error
+}
+
+// pickMarketMsg opens the markets stage as a picker for the stage that sent it
+type pickMarketMsg struct{}
+
+// marketPickedMsg returns the chosen market to the stage that opened the picker
+type marketPickedMsg struct {
+	market string
+}
+
+// pickMarket transitions to the markets stage as a picker; the chosen market is sent back to the caller as a marketPickedMsg
+func pickMarket() (Stage, tea.Cmd) {
+	return StageMarkets, func() tea.Msg {
+		return pickMarketMsg{}
+	}
+}
+
+func newMarketsModel(appStore *store.App) StageModel {
+	m := &marketsModel{
+		appStore: appStore,
+		list:     list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0),
+		spinner:  spinner.New(spinner.WithSpinner(spinner.Points)),
+	}
+	m.list.SetShowTitle(false)
+	return m
+}
+
+func (m *marketsModel) Init(dispatch StageDispatcher) tea.Cmd {
+	m.dispatch = dispatch
+	m.picking = false
+	m.err = nil
+	m.setSize()
+	// a fetch still loading from an earlier visit reports to that visit, so it is started again
+	if m.markets == nil {
+		return m.fetch()
+	}
+	return nil
+}
+
+func (m *marketsModel) setSize() {
+	h, v := listStyle.GetFrameSize()
+	m.list.SetSize(m.appStore.UI.WindowWidth-h, m.appStore.UI.WindowHeight-v-2)
+}
+
+func (m *marketsModel) Update(msg tea.Msg) (Stage, StageModel, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.setSize()
+	case marketsMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return StageMarkets, m, nil
+		}
+		m.err = nil
+		m.markets = msg.markets
+		return StageMarkets, m, m.setItems("")
+	case pickMarketMsg:
+		m.picking = true
+		return StageMarkets, m, nil
+	case spinner.TickMsg:
+		if m.loading {
+			m.spinner, cmd = m.spinner.Update(msg)
+		}
+		return StageMarkets, m, cmd
+	case tea.KeyMsg:
+		if m.list.FilterState() == list.Filtering {
+			break
+		}
+
+		switch {
+		case m.picking && key.Matches(msg, keys.Select):
+			if selected, ok := m.list.SelectedItem().(marketItem); ok {
+				return StageBack, m, func() tea.Msg {
+					return marketPickedMsg{market: selected.name}
+				}
+			}
+			return StageMarkets, m, nil
+		case key.Matches(msg, keys.Favorite):
+			return StageMarkets, m, m.toggleFavorite()
+		case key.Matches(msg, keys.Refresh) && !m.loading:
+			return StageMarkets, m, m.fetch()
+		}
+	}
+
+	m.list, cmd = m.list.Update(msg)
+	return StageMarkets, m, cmd
+}
+
+func (m *marketsModel) fetch() tea.Cmd {
+	traderProvider, err := m.appStore.Connected()
+	if err != nil {
+		m.err = err
+		return nil
+	}
+
+	m.loading = true
+	m.err = nil
+	timeout := queryTimeout(m.appStore)
+	dispatch := m.dispatch
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		markets, err := traderProvider.GetMarkets(ctx)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				err = fmt.Errorf("markets timed out after %v: %w", timeout, err)
+			}
+			dispatch(marketsMsg{err: err})
+			return
+		}
+		dispatch(marketsMsg{markets: markets.Markets})
+	}()
+	return m.spinner.Tick
+}
+
+// setItems lists favorites first, then the remaining markets by name, keeping selected highlighted if given
+func (m *marketsModel) setItems(selected string) tea.Cmd {
+	names := make([]string, 0, len(m.markets))
+	for name := range m.markets {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := m.appStore.IsFavorite(names[i]), m.appStore.IsFavorite(names[j])
+		if a != b {
+			return a
+		}
+		return names[i] < names[j]
+	})
+
+	items := make([]list.Item, 0, len(names))
+	for _, name := range names {
+		items = append(items, newMarketItem(name, m.markets[name], m.appStore.IsFavorite(name)))
+	}
+	cmd := m.list.SetItems(items)
+
+	if m.list.FilterState() == list.Unfiltered {
+		for i, name := range names {
+			if name == selected {
+				m.list.Select(i)
+			}
+		}
+	}
+	return cmd
+}
+
+// toggleFavorite stars or unstars the highlighted market and saves it to the config file
+func (m *marketsModel) toggleFavorite() tea.Cmd {
+	selected, ok := m.list.SelectedItem().(marketItem)
+	if !ok {
+		return nil
+	}
+
+	m.appStore.ToggleFavorite(selected.name)
+	if err := m.appStore.Save(); err != nil {
+		m.err = fmt.Errorf("could not save favorites: %w", err)
+	}
+	return m.setItems(selected.name)
+}
+
+func (m *marketsModel) CapturesKey(msg tea.KeyMsg) bool {
+	return m.list.FilterState() != list.Unfiltered
+}
+
+// Leave stops reporting a loading fetch: its result is dropped once the stage is left, and Init fetches again if
+// no markets were loaded
+func (m *marketsModel) Leave() {
+	m.loading = false
+}
+
+func (m *marketsModel) Busy() bool {
+	return m.loading
+}
+
+func (m *marketsModel) Bindings() []key.Binding {
+	bindings := []key.Binding{m.list.KeyMap.CursorUp, m.list.KeyMap.CursorDown, m.list.KeyMap.Filter, keys.Favorite, keys.Refresh}
+	if m.picking {
+		bindings = append([]key.Binding{keys.Select}, bindings...)
+	}
+	return bindings
+}
+
+func (m *marketsModel) View() string {
+	var b strings.Builder
+
+	switch {
+	case m.loading:
+		b.WriteString(m.spinner.View())
+		b.WriteString(" loading markets…\n")
+	case m.err != nil && m.markets == nil:
+		b.WriteString(errorStyle.Render(m.err.Error()))
+		b.WriteRune('\n')
+		b.WriteString(helpStyle.Render(fmt.Sprintf("(%v to retry)", keys.Refresh.Help().Key)))
+		return b.String()
+	}
+
+	if m.picking {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("(%v to pick a market)", keys.Select.Help().Key)))
+		b.WriteRune('\n')
+	}
+	b.WriteString(listStyle.Render(m.list.View()))
+	if m.err != nil {
+		b.WriteRune('\n')
+		b.WriteString(errorStyle.Render(m.err.Error()))
+	}
+	return b.String()
+}
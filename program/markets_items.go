@@ -0,0 +1,50 @@
+package program
+
+import (
+	"fmt"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+)
+
+type marketItem struct {
+	name          string
+	address       string
+	status        pb.MarketStatus
+	baseMint      string
+	quoteMint     string
+	baseDecimals  int64
+	quoteDecimals int64
+	project       pb.Project
+
+	favorite bool
+}
+
+func (i marketItem) Title() string {
+	star := "  "
+	if i.favorite {
+		star = "★ "
+	}
+	return fmt.Sprintf("%v%v (%v)", star, i.name, i.address)
+}
+
+// Description lists the mints and their decimals; the API does not expose tick and lot sizes
+func (i marketItem) Description() string {
+	return fmt.Sprintf("base: %v (%v dp) • quote: %v (%v dp) • %v • %v", i.baseMint, i.baseDecimals, i.quoteMint, i.quoteDecimals, i.status, i.project)
+}
+
+func (i marketItem) FilterValue() string {
+	return i.name
+}
+
+func newMarketItem(name string, market *pb.Market, favorite bool) marketItem {
+	return marketItem{
+		name:          name,
+		address:       market.Address,
+		status:        market.Status,
+		baseMint:      market.BaseMint,
+		quoteMint:     market.QuotedMint,
+		baseDecimals:  market.BaseDecimals,
+		quoteDecimals: market.QuoteDecimals,
+		project:       market.Project,
+		favorite:      favorite,
+	}
+}
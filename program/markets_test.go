@@ -0,0 +1,96 @@
+package program
+
+import (
+	"context"
+	"github.com/aspin/solana-trader-tui/store"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	tea "github.com/charmbracelet/bubbletea"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newFakeWithMarkets(names ...string) *store.FakeProvider {
+	fake := store.NewFakeProvider()
+	for _, name := range names {
+		fake.Markets[name] = &pb.Market{Market: name}
+	}
+	return fake
+}
+
+func TestMarketsFavoritesFirst(t *testing.T) {
+	appStore := newTestApp(t, newFakeWithMarkets("BTC/USDC", "ETH/USDC", "SOL/USDC"))
+	d := newStageDriver(t, newMarketsModel(appStore))
+	m := d.model.(*marketsModel)
+	d.until("the markets", func() bool {
+		return !m.loading && len(m.list.Items()) == 3
+	})
+
+	d.keys("j", "j", "*")
+	if !appStore.IsFavorite("SOL/USDC") {
+		t.Fatal("SOL/USDC not starred")
+	}
+	if m.err != nil {
+		t.Fatalf("err = %v", m.err)
+	}
+	if first := m.list.Items()[0].(marketItem).name; first != "SOL/USDC" {
+		t.Errorf("first market = %v, want the favorite", first)
+	}
+	if selected := m.list.SelectedItem().(marketItem).name; selected != "SOL/USDC" {
+		t.Errorf("selected market = %v, want the starred one kept highlighted", selected)
+	}
+}
+
+func TestMarketsPick(t *testing.T) {
+	d := newStageDriver(t, newMarketsModel(newTestApp(t, newFakeWithMarkets("SOL/USDC"))))
+	m := d.model.(*marketsModel)
+	d.until("the markets", func() bool {
+		return !m.loading && len(m.list.Items()) == 1
+	})
+
+	d.update(pickMarketMsg{})
+	stage, _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if stage != StageBack || cmd == nil {
+		t.Fatalf("stage = %v after picking, want back with the market", stage)
+	}
+	if msg, ok := cmd().(marketPickedMsg); !ok || msg.market != "SOL/USDC" {
+		t.Errorf("picked %v, want SOL/USDC", msg)
+	}
+}
+
+// hangingMarkets never responds with markets, until the context finishes
+type hangingMarkets struct {
+	*store.FakeProvider
+}
+
+func (p hangingMarkets) GetMarkets(ctx context.Context) (*pb.GetMarketsResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestMarketsTimeout(t *testing.T) {
+	appStore := newTestApp(t, hangingMarkets{store.NewFakeProvider()})
+	appStore.QueryTimeout = 50 * time.Millisecond
+	d := newStageDriver(t, newMarketsModel(appStore))
+	m := d.model.(*marketsModel)
+
+	d.until("the markets to time out", func() bool {
+		return !m.loading && m.err != nil
+	})
+	if !strings.Contains(m.err.Error(), "timed out after 50ms") {
+		t.Errorf("err = %v, want the timeout reported", m.err)
+	}
+}
+
+func TestMarketsLeaveWhileLoading(t *testing.T) {
+	d := newStageDriver(t, newMarketsModel(newTestApp(t, hangingMarkets{store.NewFakeProvider()})))
+	m := d.model.(*marketsModel)
+
+	if !m.Busy() {
+		t.Fatal("not busy while loading")
+	}
+	m.Leave()
+	if m.Busy() {
+		t.Error("still busy after leaving, so quitting from another stage asks to confirm")
+	}
+}
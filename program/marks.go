@@ -0,0 +1,193 @@
+package program
+
+import (
+	"fmt"
+	"github.com/aspin/solana-trader-tui/component/listquery"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"sync/atomic"
+)
+
+// markable is a result that can be marked to be acted on with others, showing the status of the last attempt
+type markable[T any] interface {
+	list.Item
+	Key() string
+	isMarked() bool
+	withMark(marked bool) T
+	withStatus(status string) T
+}
+
+// markStatusMsg reports the outcome of acting on a single item
+type markStatusMsg struct {
+	key    string
+	status string
+	ok     bool
+}
+
+// markDoneMsg reports that every item has been acted on
+type markDoneMsg struct {
+	succeeded int
+	total     int
+}
+
+// marks is the mark, confirm and submit state of results acted on together, such as open orders to cancel or funds
+// to settle. Marks and the statuses of the last run are kept across refreshes of the results.
+type marks[T markable[T]] struct {
+	confirming []T
+	running    bool
+	statuses   map[string]string
+	summary    string
+
+	// inflight counts the runs still submitting, including those abandoned by leaving the stage
+	inflight int32
+}
+
+func (s *marks[T]) reset() {
+	s.confirming = nil
+	s.running = false
+	s.statuses = make(map[string]string)
+	s.summary = ""
+}
+
+// items returns every markable result
+func (s *marks[T]) items(lq *listquery.Model) []T {
+	var items []T
+	for _, item := range lq.Items() {
+		if t, ok := item.(T); ok {
+			items = append(items, t)
+		}
+	}
+	return items
+}
+
+// selection returns the marked results, or the highlighted one if none are marked
+func (s *marks[T]) selection(lq *listquery.Model) []T {
+	var marked []T
+	for _, item := range s.items(lq) {
+		if item.isMarked() {
+			marked = append(marked, item)
+		}
+	}
+	if len(marked) == 0 {
+		if selected, ok := lq.SelectedItem().(T); ok {
+			marked = []T{selected}
+		}
+	}
+	return marked
+}
+
+// update applies fn to every markable result
+func (s *marks[T]) update(lq *listquery.Model, fn func(T) T) tea.Cmd {
+	items := lq.Items()
+	updated := make([]list.Item, 0, len(items))
+	for _, item := range items {
+		if t, ok := item.(T); ok {
+			item = fn(t)
+		}
+		updated = append(updated, item)
+	}
+	return lq.SetItems(updated)
+}
+
+func (s *marks[T]) toggle(lq *listquery.Model) tea.Cmd {
+	selected, ok := lq.SelectedItem().(T)
+	if !ok {
+		return nil
+	}
+	return s.update(lq, func(item T) T {
+		if item.Key() == selected.Key() {
+			return item.withMark(!item.isMarked())
+		}
+		return item
+	})
+}
+
+// confirm returns the items awaiting confirmation if msg confirms them; any other key declines
+func (s *marks[T]) confirm(msg tea.KeyMsg) ([]T, bool) {
+	items := s.confirming
+	s.confirming = nil
+	return items, key.Matches(msg, keys.Confirm)
+}
+
+// start shows status on items while they are acted on
+func (s *marks[T]) start(lq *listquery.Model, items []T, status string) tea.Cmd {
+	s.summary = ""
+	s.running = true
+	s.statuses = make(map[string]string)
+	for _, item := range items {
+		s.statuses[item.Key()] = status
+	}
+	return s.update(lq, func(item T) T {
+		if status, ok := s.statuses[item.Key()]; ok {
+			return item.withStatus(status)
+		}
+		return item
+	})
+}
+
+// status shows the outcome of acting on an item, unmarking it if it succeeded
+func (s *marks[T]) status(lq *listquery.Model, msg markStatusMsg) tea.Cmd {
+	s.statuses[msg.key] = msg.status
+	return s.update(lq, func(item T) T {
+		if item.Key() == msg.key {
+			return item.withStatus(msg.status).withMark(item.isMarked() && !msg.ok)
+		}
+		return item
+	})
+}
+
+// done summarises the run, returning false if it was abandoned by re-entering the stage
+func (s *marks[T]) done(msg markDoneMsg, noun string) bool {
+	if !s.running {
+		return false
+	}
+	s.running = false
+	s.summary = fmt.Sprintf("%v of %v %v submitted", msg.succeeded, msg.total, noun)
+	return true
+}
+
+// restore carries marks and statuses over to refreshed results
+func (s *marks[T]) restore(lq *listquery.Model, results []list.Item) {
+	marked := make(map[string]bool)
+	for _, item := range s.items(lq) {
+		if item.isMarked() {
+			marked[item.Key()] = true
+		}
+	}
+	for i, item := range results {
+		if t, ok := item.(T); ok {
+			results[i] = t.withMark(marked[t.Key()]).withStatus(s.statuses[t.Key()])
+		}
+	}
+}
+
+// run starts fn in the background, counting it as in flight until it returns even once its results are dropped
+func (s *marks[T]) run(fn func()) {
+	atomic.AddInt32(&s.inflight, 1)
+	go func() {
+		defer atomic.AddInt32(&s.inflight, -1)
+		fn()
+	}()
+}
+
+// busy reports whether any run is still submitting
+func (s *marks[T]) busy() bool {
+	return atomic.LoadInt32(&s.inflight) > 0
+}
+
+// runMarked acts on each item in turn with fn, dispatching the outcome of each and then the totals. It is started
+// with run, with the dispatcher captured when the run started.
+func runMarked[T markable[T]](dispatch StageDispatcher, items []T, action, past string, fn func(T) (string, error)) {
+	succeeded := 0
+	for _, item := range items {
+		signature, err := fn(item)
+		if err != nil {
+			dispatch(markStatusMsg{key: item.Key(), status: fmt.Sprintf("%v failed: %v", action, err)})
+			continue
+		}
+		succeeded++
+		dispatch(markStatusMsg{key: item.Key(), status: fmt.Sprintf("%v: %v", past, signature), ok: true})
+	}
+	dispatch(markDoneMsg{succeeded: succeeded, total: len(items)})
+}
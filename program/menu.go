@@ -2,6 +2,7 @@ package program
 
 import (
 	"github.com/aspin/solana-trader-tui/store"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -41,9 +42,8 @@ func (m *menuModel) Update(msg tea.Msg) (Stage, StageModel, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.setSize()
 	case tea.KeyMsg:
-		switch msg.Type {
 		// transition to other stage
-		case tea.KeyEnter, tea.KeySpace:
+		if key.Matches(msg, keys.Select) && m.list.FilterState() != list.Filtering {
 			listIndex := m.list.Index()
 			stage := m.list.Items()[listIndex].(menuItem).stage
 			return stage, m, nil
@@ -55,6 +55,14 @@ func (m *menuModel) Update(msg tea.Msg) (Stage, StageModel, tea.Cmd) {
 	return StageMenu, m, cmd
 }
 
+func (m *menuModel) CapturesKey(msg tea.KeyMsg) bool {
+	return m.list.FilterState() != list.Unfiltered
+}
+
+func (m *menuModel) Bindings() []key.Binding {
+	return []key.Binding{keys.Select, m.list.KeyMap.CursorUp, m.list.KeyMap.CursorDown, m.list.KeyMap.Filter}
+}
+
 func (m *menuModel) View() string {
 	return listStyle.Render(m.list.View())
 }
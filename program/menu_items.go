@@ -26,17 +26,64 @@ var defaultMenuItems = []list.Item{
 		desc:  "Set app details such as private/public key, auth header, etc.",
 		stage: StageSettings,
 	},
+	menuItem{
+		title: "Profiles",
+		desc:  "Switch between or add named wallet profiles",
+		stage: StageProfiles,
+	},
+	menuItem{
+		title: "Markets",
+		desc:  "Browse and star dex markets",
+		stage: StageMarkets,
+	},
+	menuItem{
+		title: "Tickers",
+		desc:  "Watch best bid/ask and spread for all or favorite markets",
+		stage: StageTickers,
+	},
+	menuItem{
+		title: "Balances",
+		desc:  "View wallet, unsettled and open orders balances with their USD value",
+		stage: StageBalances,
+	},
+	menuItem{
+		title: "Notifications",
+		desc:  "Fills, partial fills and cancels of your orders in watched markets",
+		stage: StageNotifications,
+	},
 	menuItem{
 		title: "Open Orders",
 		desc:  "View your unfilled open orders in a dex market",
 		stage: StageOpenOrders,
 	},
+	menuItem{
+		title: "Order Entry",
+		desc:  "Place a limit, IOC or post-only order in a dex market",
+		stage: StageOrderEntry,
+	},
+	menuItem{
+		title: "Settle Funds",
+		desc:  "Settle unsettled funds from your open orders accounts",
+		stage: StageSettle,
+	},
+	menuItem{
+		title: "Swap",
+		desc:  "Swap tokens through the best quoted Jupiter or Raydium route",
+		stage: StageSwap,
+	},
 	menuItem{
 		title: "Orderbook",
 		desc:  "View all asks and bids in a dex market",
+		stage: StageOrderbook,
 	},
 	menuItem{
 		title: "Stream Orderbook",
 		desc:  "View stream of orderbook updates in a dex market",
+		stage: StageStream,
+	},
+	menuItem{
+		title: "Time & Sales",
+		desc:  "View a live tape of trades in a dex market",
+		stage: StageTimeSales,
 	},
 }
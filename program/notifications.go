@@ -0,0 +1,299 @@
+package program
+
+import (
+	"context"
+	"fmt"
+	"github.com/aspin/solana-trader-tui/store"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	toastDuration    = 5 * time.Second
+	maxToasts        = 3
+	maxNotifications = 500
+)
+
+// notificationsModel watches the order status stream of the configured owner and markets in the background,
+// whichever stage is active, and is also the stage listing the notifications received
+type notificationsModel struct {
+	appStore *store.App
+
+	// send is the program's unscoped dispatcher: the streams report whichever stage is current, so their messages
+	// must not be tied to a stage's request
+	send StageDispatcher
+
+	// provider, owner and markets are what the current streams were opened with
+	provider store.TraderProvider
+	owner    string
+	markets  string
+	streamID int
+	cancel   context.CancelFunc
+	errs     map[string]error
+
+	// notifications is most recent first
+	notifications []notification
+	offset        int
+}
+
+type notification struct {
+	received time.Time
+	status   pb.OrderStatus
+	text     string
+}
+
+type orderStatusMsg struct {
+	streamID int
+	market   string
+	update   *pb.GetOrderStatusResponse
+}
+
+type orderStatusErrMsg struct {
+	streamID int
+	market   string
+	err      error
+}
+
+type toastExpiredMsg struct{}
+
+func newNotificationsModel(appStore *store.App) *notificationsModel {
+	return &notificationsModel{
+		appStore: appStore,
+		errs:     make(map[string]error),
+	}
+}
+
+// watch (re)opens the order status streams when the provider, owner or watched markets have changed since they were last opened
+func (m *notificationsModel) watch(send StageDispatcher) {
+	m.send = send
+
+	settings := m.appStore.CurrentSettings()
+	owner := ""
+	if !settings.PublicKey.IsZero() {
+		owner = settings.PublicKey.String()
+	}
+	markets := m.appStore.OrderStatusMarkets
+	if len(markets) == 0 {
+		markets = m.appStore.Favorites
+	}
+	// a disconnected profile has no provider, closing the streams until it connects
+	provider, _ := m.appStore.Connected()
+	if provider == m.provider && owner == m.owner && strings.Join(markets, ",") == m.markets {
+		return
+	}
+
+	m.stop()
+	m.provider = provider
+	m.owner = owner
+	m.markets = strings.Join(markets, ",")
+	if provider == nil || owner == "" {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.streamID++
+	m.cancel = cancel
+	streamID := m.streamID
+	project := settings.Project
+	for _, market := range markets {
+		market := market
+		go func() {
+			stream, err := provider.GetOrderStatusStream(ctx, market, owner, project)
+			if err != nil {
+				send(orderStatusErrMsg{streamID: streamID, market: market, err: err})
+				return
+			}
+
+			for {
+				update, err := stream()
+				if err != nil {
+					if ctx.Err() == nil {
+						send(orderStatusErrMsg{streamID: streamID, market: market, err: err})
+					}
+					return
+				}
+				if update.OrderInfo != nil {
+					send(orderStatusMsg{streamID: streamID, market: market, update: update.OrderInfo})
+				}
+			}
+		}()
+	}
+}
+
+// stop cancels the streams' context, ending their receiving goroutines
+func (m *notificationsModel) stop() {
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+	m.provider = nil
+	m.errs = make(map[string]error)
+}
+
+// handle processes the background stream messages, whichever stage is active
+func (m *notificationsModel) handle(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case orderStatusMsg:
+		if msg.streamID != m.streamID {
+			return nil
+		}
+		text, ok := orderStatusText(msg.market, msg.update)
+		if !ok {
+			return nil
+		}
+		return m.notify(msg.update.OrderStatus, text)
+	case orderStatusErrMsg:
+		if msg.streamID != m.streamID {
+			return nil
+		}
+		m.errs[msg.market] = msg.err
+	}
+	return nil
+}
+
+func (m *notificationsModel) notify(status pb.OrderStatus, text string) tea.Cmd {
+	m.notifications = append([]notification{{received: time.Now(), status: status, text: text}}, m.notifications...)
+	if len(m.notifications) > maxNotifications {
+		m.notifications = m.notifications[:maxNotifications]
+	}
+	if m.offset > 0 {
+		m.offset++
+	}
+	return tea.Tick(toastDuration, func(time.Time) tea.Msg {
+		return toastExpiredMsg{}
+	})
+}
+
+// orderStatusText describes fills, partial fills and cancels; other updates are not notified
+func orderStatusText(market string, update *pb.GetOrderStatusResponse) (string, bool) {
+	side := "bid"
+	if update.Side == pb.Side_S_ASK {
+		side = "ask"
+	}
+	order := fmt.Sprintf("%v %v %v @ %v", market, side, update.OrderID, formatFloat(float64(update.OrderPrice)))
+
+	switch update.OrderStatus {
+	case pb.OrderStatus_OS_FILLED:
+		return fmt.Sprintf("filled: %v at %v", order, formatFloat(float64(update.FillPrice))), true
+	case pb.OrderStatus_OS_PARTIAL_FILL:
+		return fmt.Sprintf("partially filled: %v at %v, %v remaining", order, formatFloat(float64(update.FillPrice)), formatFloat(float64(update.QuantityRemaining))), true
+	case pb.OrderStatus_OS_CANCELLED:
+		return fmt.Sprintf("cancelled: %v, %v released", order, formatFloat(float64(update.QuantityReleased))), true
+	default:
+		return "", false
+	}
+}
+
+func notificationStyle(status pb.OrderStatus) lipgloss.Style {
+	if status == pb.OrderStatus_OS_CANCELLED {
+		return confirmStyle
+	}
+	return statusStyle
+}
+
+// toastsView lists the notifications received within the toast duration, most recent first
+func (m *notificationsModel) toastsView() string {
+	var b strings.Builder
+
+	now := time.Now()
+	for i, n := range m.notifications {
+		if i == maxToasts || now.Sub(n.received) >= toastDuration {
+			break
+		}
+		b.WriteString(notificationStyle(n.status).Render("● " + n.text))
+		b.WriteRune('\n')
+	}
+	return b.String()
+}
+
+// Init leaves the streams' dispatcher alone: the stage-scoped one would drop their messages on every other stage
+func (m *notificationsModel) Init(dispatch StageDispatcher) tea.Cmd {
+	m.offset = 0
+	return nil
+}
+
+func (m *notificationsModel) Update(msg tea.Msg) (Stage, StageModel, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, keys.ScrollUp):
+			if m.offset > 0 {
+				m.offset--
+			}
+		case key.Matches(msg, keys.ScrollDown):
+			if m.offset < len(m.notifications)-1 {
+				m.offset++
+			}
+		case key.Matches(msg, keys.Refresh):
+			// reopen the streams, e.g. after an error: stop clears what they were opened with, so watch opens them again
+			m.stop()
+			m.watch(m.send)
+		}
+	}
+	return StageNotifications, m, nil
+}
+
+// Busy reports the open streams, as quitting would miss fills and cancels they would notify
+func (m *notificationsModel) Busy() bool {
+	return m.cancel != nil
+}
+
+func (m *notificationsModel) Bindings() []key.Binding {
+	return []key.Binding{keys.ScrollUp, keys.ScrollDown, keys.Refresh}
+}
+
+func (m *notificationsModel) View() string {
+	var b strings.Builder
+
+	switch {
+	case m.cancel == nil:
+		b.WriteString(helpStyle.Render("not watching: connect with a public key set to receive order status updates"))
+	case m.markets == "":
+		b.WriteString(helpStyle.Render("not watching: star markets or set orderStatusMarkets in the config file"))
+	default:
+		b.WriteString(helpStyle.Render(fmt.Sprintf("watching %v for %v", strings.ReplaceAll(m.markets, ",", ", "), m.owner)))
+	}
+	b.WriteString("\n\n")
+
+	markets := make([]string, 0, len(m.errs))
+	for market := range m.errs {
+		markets = append(markets, market)
+	}
+	sort.Strings(markets)
+	for _, market := range markets {
+		b.WriteString(errorStyle.Render(fmt.Sprintf("%v: %v", market, m.errs[market])))
+		b.WriteRune('\n')
+	}
+	if len(m.errs) > 0 {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("(%v to reconnect)", keys.Refresh.Help().Key)))
+		b.WriteString("\n\n")
+	}
+
+	if len(m.notifications) == 0 {
+		b.WriteString("no fills or cancels yet\n")
+		return b.String()
+	}
+
+	// leave room for the status, error and help lines
+	height := m.appStore.UI.WindowHeight - 8 - 2*len(m.errs)
+	if height < 1 {
+		height = len(m.notifications)
+	}
+	end := m.offset + height
+	if end > len(m.notifications) {
+		end = len(m.notifications)
+	}
+	for _, n := range m.notifications[m.offset:end] {
+		b.WriteString(notificationStyle(n.status).Render(fmt.Sprintf("%v %v", n.received.Format("15:04:05"), n.text)))
+		b.WriteRune('\n')
+	}
+	if len(m.notifications) > height {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("%v-%v of %v notifications", m.offset+1, end, len(m.notifications))))
+		b.WriteRune('\n')
+	}
+	return b.String()
+}
@@ -0,0 +1,53 @@
+package program
+
+import (
+	"github.com/aspin/solana-trader-tui/store"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"strings"
+	"testing"
+)
+
+func TestNotificationsFollowProfile(t *testing.T) {
+	appStore := newTestApp(t, store.NewFakeProvider())
+	appStore.OrderStatusMarkets = []string{"SOL/USDC"}
+	m := newNotificationsModel(appStore)
+	d := newStageDriver(t, m)
+	defer m.stop()
+
+	m.watch(d.send)
+	if !m.Busy() || m.markets != "SOL/USDC" {
+		t.Fatalf("watching %q, want the configured market", m.markets)
+	}
+
+	if err := appStore.AddProfile("other"); err != nil {
+		t.Fatal(err)
+	}
+	if err := appStore.SwitchProfile("other"); err != nil {
+		t.Fatal(err)
+	}
+	m.watch(d.send)
+	if m.Busy() {
+		t.Error("still watching the previous profile's orders after switching")
+	}
+}
+
+func TestNotificationsHandle(t *testing.T) {
+	m := newNotificationsModel(newTestApp(t, store.NewFakeProvider()))
+	m.streamID = 1
+
+	filled := &pb.GetOrderStatusResponse{OrderID: "1", Side: pb.Side_S_BID, OrderPrice: 10, FillPrice: 10, OrderStatus: pb.OrderStatus_OS_FILLED}
+	open := &pb.GetOrderStatusResponse{OrderID: "2", OrderStatus: pb.OrderStatus_OS_OPEN}
+	m.handle(orderStatusMsg{streamID: 1, market: "SOL/USDC", update: filled})
+	m.handle(orderStatusMsg{streamID: 1, market: "SOL/USDC", update: open})
+	m.handle(orderStatusMsg{streamID: 0, market: "SOL/USDC", update: filled})
+
+	if len(m.notifications) != 1 {
+		t.Fatalf("%v notifications, want only the fill from the current stream", len(m.notifications))
+	}
+	if text := m.notifications[0].text; text != "filled: SOL/USDC bid 1 @ 10 at 10" {
+		t.Errorf("text = %q", text)
+	}
+	if toasts := m.toastsView(); !strings.Contains(toasts, "filled") {
+		t.Errorf("toasts = %q, want the fill shown", toasts)
+	}
+}
@@ -2,24 +2,62 @@ package program
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/aspin/solana-trader-tui/component/listquery"
 	"github.com/aspin/solana-trader-tui/store"
+	"github.com/bloXroute-Labs/solana-trader-client-go/provider"
 	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"strconv"
+	"strings"
 )
 
 const (
 	maxWidth = 80
 )
 
+type cancelKind int
+
+const (
+	cancelByOrderID cancelKind = iota
+	cancelByClientID
+	cancelAll
+)
+
+// cancelRequest is a cancellation awaiting confirmation
+type cancelRequest struct {
+	kind   cancelKind
+	market string
+	orders []openOrdersItem
+}
+
+func (r cancelRequest) String() string {
+	switch r.kind {
+	case cancelAll:
+		return fmt.Sprintf("Cancel all %v open orders in %v?", len(r.orders), r.market)
+	case cancelByClientID:
+		return fmt.Sprintf("Cancel %v order(s) by client order ID?", len(r.orders))
+	default:
+		return fmt.Sprintf("Cancel %v order(s)?", len(r.orders))
+	}
+}
+
 type openOrdersModel struct {
 	appStore *store.App
 	dispatch StageDispatcher
 
 	listquery listquery.Model
+
+	marks marks[openOrdersItem]
+	err   error
+
+	// cancelKind is how the orders awaiting confirmation are to be cancelled
+	cancelKind cancelKind
 }
 
 type openOrdersMsg struct {
@@ -32,18 +70,26 @@ func newOpenOrdersModel(appStore *store.App) StageModel {
 	marketInput.Focus()
 	marketInput.PromptStyle = focusedStyle
 
-	lq := listquery.New([]textinput.Model{marketInput}, spinner.Points, list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0), nil)
+	lq := listquery.New([]textinput.Model{marketInput}, spinner.Points, list.NewDefaultDelegate(), nil)
+	lq.KeyMap = listQueryKeyMap()
+	lq.Timeout = queryTimeout(appStore)
+	lq.PollInterval = pollInterval(appStore)
+	lq.SetColumns(openOrdersColumns...)
 
 	m := &openOrdersModel{
 		appStore:  appStore,
 		listquery: lq,
 	}
+	m.marks.reset()
 	m.listquery.SetQuery(m.fetchOrders)
+	m.listquery.SetHistory(appStore.History["openOrders"], recordHistory(appStore, "openOrders"))
 	return m
 }
 
 func (m *openOrdersModel) Init(dispatch StageDispatcher) tea.Cmd {
 	m.dispatch = dispatch
+	m.marks.reset()
+	m.err = nil
 	return m.listquery.Init(m.appStore.UI.WindowWidth, m.appStore.UI.WindowHeight)
 }
 
@@ -53,28 +99,226 @@ func (m *openOrdersModel) Update(msg tea.Msg) (Stage, StageModel, tea.Cmd) {
 		exit bool
 	)
 
+	switch msg := msg.(type) {
+	case markStatusMsg:
+		return StageOpenOrders, m, m.marks.status(&m.listquery, msg)
+	case markDoneMsg:
+		if !m.marks.done(msg, "cancellation(s)") {
+			return StageOpenOrders, m, nil
+		}
+		return StageOpenOrders, m, m.listquery.Refresh()
+	case marketPickedMsg:
+		m.listquery.SetInputValue(0, msg.market)
+		return StageOpenOrders, m, nil
+	case listquery.ResultMsg:
+		// orders that failed to cancel keep their status across the refresh, and marks are kept across polls
+		m.marks.restore(&m.listquery, msg.Items)
+	case tea.KeyMsg:
+		if m.listquery.Focused() == 0 && key.Matches(msg, keys.PickMarket) {
+			stage, cmd := pickMarket()
+			return stage, m, cmd
+		}
+		if m.marks.confirming != nil {
+			if orders, ok := m.marks.confirm(msg); ok {
+				return StageOpenOrders, m, m.startCancel(m.cancelRequest(orders))
+			}
+			return StageOpenOrders, m, nil
+		}
+
+		if m.listquery.Showing() && !m.marks.running {
+			switch {
+			case key.Matches(msg, keys.Mark):
+				return StageOpenOrders, m, m.marks.toggle(&m.listquery)
+			case key.Matches(msg, keys.CancelOrder):
+				m.confirmCancel(cancelByOrderID)
+				return StageOpenOrders, m, nil
+			case key.Matches(msg, keys.CancelByClientID):
+				m.confirmCancel(cancelByClientID)
+				return StageOpenOrders, m, nil
+			case key.Matches(msg, keys.CancelAll):
+				m.confirmCancel(cancelAll)
+				return StageOpenOrders, m, nil
+			case key.Matches(msg, keys.Amend):
+				if selected, ok := m.listquery.SelectedItem().(openOrdersItem); ok {
+					return StageReplaceOrder, m, func() tea.Msg {
+						return replaceOrderMsg{order: selected}
+					}
+				}
+				return StageOpenOrders, m, nil
+			}
+		}
+	}
+
 	m.listquery, cmd, exit = m.listquery.Update(msg)
 	if exit {
-		return StageMenu, m, nil
+		return StageBack, m, nil
 	}
 	return StageOpenOrders, m, cmd
 }
 
-func (m *openOrdersModel) fetchOrders(vs []string) {
+// confirmCancel asks for confirmation to cancel the marked orders, or the highlighted one if none are marked
+func (m *openOrdersModel) confirmCancel(kind cancelKind) {
+	orders := m.marks.items(&m.listquery)
+	if len(orders) == 0 {
+		m.err = errors.New("no open orders to cancel")
+		return
+	}
+	if kind != cancelAll {
+		if orders = m.marks.selection(&m.listquery); len(orders) == 0 {
+			return
+		}
+	}
+
+	m.err = nil
+	m.cancelKind = kind
+	m.marks.confirming = orders
+}
+
+func (m *openOrdersModel) cancelRequest(orders []openOrdersItem) cancelRequest {
+	return cancelRequest{kind: m.cancelKind, market: orders[0].market, orders: orders}
+}
+
+func (m *openOrdersModel) startCancel(request cancelRequest) tea.Cmd {
+	settings := m.appStore.CurrentSettings()
+	if settings.PublicKey.IsZero() {
+		m.err = errors.New("a public key is required to cancel orders: set it from the settings stage")
+		return nil
+	}
+	traderProvider, err := m.appStore.Connected()
+	if err != nil {
+		m.err = err
+		return nil
+	}
+
+	m.err = nil
+	cmd := m.marks.start(&m.listquery, request.orders, "cancelling…")
+
+	owner := settings.PublicKey.String()
+	openOrders := settings.OpenOrdersAddress.String()
+	project := settings.Project
+	dispatch := m.dispatch
+	if request.kind == cancelAll {
+		m.marks.run(func() {
+			m.cancelAll(dispatch, traderProvider, request, owner, openOrders, project)
+		})
+		return cmd
+	}
+	m.marks.run(func() {
+		runMarked(dispatch, request.orders, "cancel", "cancelled", func(order openOrdersItem) (string, error) {
+			return m.cancelOrder(traderProvider, request.kind, order, owner, openOrders, project)
+		})
+	})
+	return cmd
+}
+
+func (m *openOrdersModel) cancelOrder(traderProvider store.TraderProvider, kind cancelKind, order openOrdersItem, owner, openOrders string, project pb.Project) (string, error) {
+	ctx, cancel := submitContext()
+	defer cancel()
+
+	if kind == cancelByClientID {
+		clientOrderID, err := strconv.ParseUint(order.clientOrderID, 10, 64)
+		if err != nil || clientOrderID == 0 {
+			return "", fmt.Errorf("invalid client order ID %q", order.clientOrderID)
+		}
+		signature, err := traderProvider.SubmitCancelByClientOrderID(ctx, clientOrderID, owner, order.market, openOrders, project, false)
+		return signature, submitErr(ctx, err)
+	}
+	signature, err := traderProvider.SubmitCancelOrder(ctx, order.orderID, order.side, owner, order.market, openOrders, project, false)
+	return signature, submitErr(ctx, err)
+}
+
+func (m *openOrdersModel) cancelAll(dispatch StageDispatcher, traderProvider store.TraderProvider, request cancelRequest, owner, openOrders string, project pb.Project) {
+	ctx, cancel := submitContext()
+	defer cancel()
+
+	opts := provider.SubmitOpts{SubmitStrategy: pb.SubmitStrategy_P_SUBMIT_ALL}
+	response, err := traderProvider.SubmitCancelAll(ctx, request.market, owner, []string{openOrders}, project, opts)
+	err = submitErr(ctx, err)
+
+	status, ok := "", err == nil
+	if err != nil {
+		status = fmt.Sprintf("cancel all failed: %v", err)
+	} else {
+		signatures := make([]string, 0, len(response.Transactions))
+		for _, tx := range response.Transactions {
+			if !tx.Submitted {
+				ok = false
+				signatures = append(signatures, fmt.Sprintf("failed: %v", tx.Error))
+				continue
+			}
+			signatures = append(signatures, tx.Signature)
+		}
+		status = fmt.Sprintf("cancel all: %v", strings.Join(signatures, ", "))
+	}
+
+	for _, order := range request.orders {
+		dispatch(markStatusMsg{key: order.Key(), status: status, ok: ok})
+	}
+
+	cancelled := 0
+	if ok {
+		cancelled = len(request.orders)
+	}
+	dispatch(markDoneMsg{succeeded: cancelled, total: len(request.orders)})
+}
+
+func (m *openOrdersModel) CapturesKey(msg tea.KeyMsg) bool {
+	return m.marks.confirming != nil || m.listquery.Filtering() || m.listquery.Cancels(msg) || (m.listquery.Typing() && capturesTextKey(msg))
+}
+
+func (m *openOrdersModel) Bindings() []key.Binding {
+	bindings := m.listquery.Bindings()
+	if m.listquery.Showing() {
+		bindings = append(bindings, keys.Mark, keys.CancelOrder, keys.CancelByClientID, keys.CancelAll, keys.Amend)
+	}
+	if m.listquery.Focused() == 0 {
+		bindings = append(bindings, keys.PickMarket)
+	}
+	return bindings
+}
+
+func (m *openOrdersModel) Leave() {
+	m.listquery.Cancel()
+}
+
+func (m *openOrdersModel) Busy() bool {
+	return m.listquery.Loading() || m.marks.busy()
+}
+
+func (m *openOrdersModel) fetchOrders(ctx context.Context, vs []string) ([]list.Item, error) {
+	traderProvider, err := m.appStore.Connected()
+	if err != nil {
+		return nil, err
+	}
+
 	market := vs[0]
-	openOrders, err := m.appStore.Provider.GetOpenOrders(context.Background(), market, "", m.appStore.Settings.OpenOrdersAddress.String(), m.appStore.Settings.Project)
+	settings := m.appStore.CurrentSettings()
+	openOrders, err := traderProvider.GetOpenOrders(ctx, market, "", settings.OpenOrdersAddress.String(), settings.Project)
 	if err != nil {
-		m.dispatch(listquery.ErrorMsg{Err: err})
-		return
+		return nil, err
 	}
 
 	items := make([]list.Item, 0)
 	for _, order := range openOrders.Orders {
-		items = append(items, newOpenOrdersItem(order))
+		items = append(items, newOpenOrdersItem(order, market))
 	}
-	m.dispatch(listquery.ResultMsg{Items: items})
+	return items, nil
 }
 
 func (m openOrdersModel) View() string {
-	return m.listquery.View()
+	var b strings.Builder
+	b.WriteString(m.listquery.View())
+
+	b.WriteRune('\n')
+	switch {
+	case m.marks.confirming != nil:
+		b.WriteString(confirmStyle.Render(fmt.Sprintf("%v (%v/n)", m.cancelRequest(m.marks.confirming), keys.Confirm.Help().Key)))
+	case m.err != nil:
+		b.WriteString(errorStyle.Render(m.err.Error()))
+	case m.marks.running:
+		b.WriteString(statusStyle.Render("Submitting cancellations…"))
+	case m.marks.summary != "":
+		b.WriteString(statusStyle.Render(m.marks.summary))
+	}
+	return b.String()
 }
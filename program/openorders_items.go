@@ -1,35 +1,80 @@
 package program
 
 import (
-	"fmt"
+	"github.com/aspin/solana-trader-tui/component/listquery"
 	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"strings"
 )
 
+var openOrdersColumns = []listquery.Column{
+	{Title: ""},
+	{Title: "SIDE"},
+	{Title: "ORDER ID"},
+	{Title: "CLIENT ID"},
+	{Title: "PRICE", Numeric: true},
+	{Title: "REMAINING", Numeric: true},
+	{Title: "TYPES"},
+	{Title: "STATUS"},
+}
+
 type openOrdersItem struct {
 	orderID       string
-	side          string
+	market        string
+	side          pb.Side
 	types         []pb.OrderType
 	price         float64
 	remainingSize float64
 	clientOrderID string
+
+	// marked orders are cancelled together; status reports the last cancel attempt
+	marked bool
+	status string
+}
+
+func (i openOrdersItem) FilterValue() string {
+	return i.orderID
+}
+
+func (i openOrdersItem) Cells() []string {
+	mark := ""
+	if i.marked {
+		mark = "●"
+	}
+	side := "bid"
+	if i.side == pb.Side_S_ASK {
+		side = "ask"
+	}
+	types := make([]string, 0, len(i.types))
+	for _, t := range i.types {
+		types = append(types, strings.TrimPrefix(t.String(), "OT_"))
+	}
+	return []string{mark, side, i.orderID, i.clientOrderID, formatFloat(i.price), formatFloat(i.remainingSize), strings.Join(types, ","), i.status}
 }
 
-func (i openOrdersItem) Title() string {
-	return fmt.Sprintf("[%v] %v (%v)", i.side, i.orderID, i.clientOrderID)
+// Key identifies the order across polls
+func (i openOrdersItem) Key() string {
+	return i.market + "/" + i.orderID
 }
 
-func (i openOrdersItem) Description() string {
-	return fmt.Sprintf("%v @ %v; types: %v", i.price, i.remainingSize, i.types)
+func (i openOrdersItem) isMarked() bool {
+	return i.marked
 }
 
-func (i openOrdersItem) FilterValue() string {
-	return i.orderID
+func (i openOrdersItem) withMark(marked bool) openOrdersItem {
+	i.marked = marked
+	return i
+}
+
+func (i openOrdersItem) withStatus(status string) openOrdersItem {
+	i.status = status
+	return i
 }
 
-func newOpenOrdersItem(order *pb.Order) openOrdersItem {
+func newOpenOrdersItem(order *pb.Order, market string) openOrdersItem {
 	return openOrdersItem{
 		orderID:       order.OrderID,
-		side:          order.Side.String(),
+		market:        market,
+		side:          order.Side,
 		types:         order.Types,
 		price:         order.Price,
 		remainingSize: order.RemainingSize,
@@ -0,0 +1,168 @@
+package program
+
+import (
+	"context"
+	"errors"
+	"github.com/aspin/solana-trader-tui/store"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	tea "github.com/charmbracelet/bubbletea"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newFakeWithOrders(market string, orderIDs ...string) *store.FakeProvider {
+	fake := store.NewFakeProvider()
+	for _, orderID := range orderIDs {
+		fake.OpenOrders[market] = append(fake.OpenOrders[market], &pb.Order{OrderID: orderID, Market: market, Side: pb.Side_S_BID, Price: 10, RemainingSize: 1})
+	}
+	return fake
+}
+
+func TestOpenOrdersFetch(t *testing.T) {
+	d := newStageDriver(t, newOpenOrdersModel(newTestApp(t, newFakeWithOrders("SOL/USDC", "order-1"))))
+
+	d.keys("SOL/USDC", "enter", "enter")
+	d.until("the open orders", func() bool {
+		return strings.Contains(d.model.View(), "order-1")
+	})
+}
+
+func TestOpenOrdersFetchError(t *testing.T) {
+	fake := newFakeWithOrders("SOL/USDC", "order-1")
+	fake.Err = errors.New("rejected")
+	d := newStageDriver(t, newOpenOrdersModel(newTestApp(t, fake)))
+
+	d.keys("SOL/USDC", "enter", "enter")
+	d.until("the error", func() bool {
+		return strings.Contains(d.model.View(), "rejected")
+	})
+}
+
+func TestOpenOrdersCancelSelected(t *testing.T) {
+	fake := newFakeWithOrders("SOL/USDC", "1", "2")
+	d := newStageDriver(t, newOpenOrdersModel(newTestApp(t, fake)))
+	m := d.model.(*openOrdersModel)
+
+	d.keys("SOL/USDC", "enter", "enter")
+	d.until("the open orders", func() bool {
+		return len(m.listquery.Items()) == 2
+	})
+
+	d.keys("c", "y")
+	d.until("the cancellation", func() bool {
+		return !m.marks.running && m.marks.summary != "" && len(m.listquery.Items()) == 1
+	})
+	if m.marks.summary != "1 of 1 cancellation(s) submitted" {
+		t.Errorf("summary = %q", m.marks.summary)
+	}
+	if n := len(fake.OpenOrders["SOL/USDC"]); n != 1 {
+		t.Errorf("%v orders left open, want 1", n)
+	}
+}
+
+func TestOpenOrdersCancelDeclined(t *testing.T) {
+	fake := newFakeWithOrders("SOL/USDC", "1")
+	d := newStageDriver(t, newOpenOrdersModel(newTestApp(t, fake)))
+	m := d.model.(*openOrdersModel)
+
+	d.keys("SOL/USDC", "enter", "enter")
+	d.until("the open orders", func() bool {
+		return len(m.listquery.Items()) == 1
+	})
+
+	d.keys("a")
+	if m.marks.confirming == nil || m.cancelKind != cancelAll {
+		t.Fatalf("confirming = %v, want cancel all", m.marks.confirming)
+	}
+	d.keys("n")
+	if m.marks.confirming != nil || m.marks.running {
+		t.Errorf("confirming = %v, cancelling = %v after declining", m.marks.confirming, m.marks.running)
+	}
+	if n := len(fake.OpenOrders["SOL/USDC"]); n != 1 {
+		t.Errorf("%v orders left open, want 1", n)
+	}
+}
+
+func TestOpenOrdersCancelFailure(t *testing.T) {
+	fake := newFakeWithOrders("SOL/USDC", "1")
+	d := newStageDriver(t, newOpenOrdersModel(newTestApp(t, fake)))
+	m := d.model.(*openOrdersModel)
+
+	d.keys("SOL/USDC", "enter", "enter")
+	d.until("the open orders", func() bool {
+		return len(m.listquery.Items()) == 1
+	})
+
+	fake.Err = errors.New("rejected")
+	d.keys("c", "y")
+	d.until("the cancellation", func() bool {
+		return !m.marks.running && m.marks.summary != ""
+	})
+	if m.marks.summary != "0 of 1 cancellation(s) submitted" {
+		t.Errorf("summary = %q", m.marks.summary)
+	}
+	if status := m.marks.statuses["SOL/USDC/1"]; status != "cancel failed: rejected" {
+		t.Errorf("status = %q", status)
+	}
+}
+
+// hangingCancels never responds to cancellations, until the context finishes
+type hangingCancels struct {
+	*store.FakeProvider
+}
+
+func (p hangingCancels) SubmitCancelOrder(ctx context.Context, orderID string, side pb.Side, owner, market, openOrders string, project pb.Project, skipPreFlight bool) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestOpenOrdersCancelTimeout(t *testing.T) {
+	defer func(timeout time.Duration) {
+		submitTimeout = timeout
+	}(submitTimeout)
+	submitTimeout = 50 * time.Millisecond
+
+	fake := newFakeWithOrders("SOL/USDC", "1")
+	d := newStageDriver(t, newOpenOrdersModel(newTestApp(t, hangingCancels{fake})))
+	m := d.model.(*openOrdersModel)
+
+	d.keys("SOL/USDC", "enter", "enter")
+	d.until("the open orders", func() bool {
+		return len(m.listquery.Items()) == 1
+	})
+
+	d.keys("c", "y")
+	d.until("the cancellation to time out", func() bool {
+		return !m.marks.running
+	})
+	if status := m.marks.statuses["SOL/USDC/1"]; !strings.Contains(status, "outcome is unknown") {
+		t.Errorf("status = %q, want the outcome reported unknown", status)
+	}
+}
+
+func TestOpenOrdersBusyAfterLeaving(t *testing.T) {
+	defer func(timeout time.Duration) {
+		submitTimeout = timeout
+	}(submitTimeout)
+	submitTimeout = 100 * time.Millisecond
+
+	fake := newFakeWithOrders("SOL/USDC", "1")
+	d := newStageDriver(t, newOpenOrdersModel(newTestApp(t, hangingCancels{fake})))
+	m := d.model.(*openOrdersModel)
+
+	d.keys("SOL/USDC", "enter", "enter")
+	d.until("the open orders", func() bool {
+		return len(m.listquery.Items()) == 1
+	})
+
+	d.keys("c", "y")
+	m.Leave()
+	m.Init(func(tea.Msg) {})
+	if !m.Busy() {
+		t.Fatal("not busy after leaving with a cancellation in flight")
+	}
+	d.until("the abandoned cancellation to finish", func() bool {
+		return !m.Busy()
+	})
+}
@@ -0,0 +1,393 @@
+package program
+
+import (
+	"errors"
+	"fmt"
+	"github.com/aspin/solana-trader-tui/store"
+	"github.com/bloXroute-Labs/solana-trader-client-go/provider"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// takerFeeRate is the OpenBook base tier taker fee, used to estimate fees in the preview. Post-only orders never take liquidity.
+const takerFeeRate = 0.0004
+
+type orderEntryState int
+
+const (
+	oeInput orderEntryState = iota
+	oePreview
+	oeSubmitting
+	oeDone
+)
+
+// order entry input indexes
+const (
+	oeMarket = iota
+	oeSide
+	oeType
+	oePrice
+	oeSize
+	oeClientOrderID
+	oePayer
+	oeInputCount
+)
+
+type orderEntryModel struct {
+	appStore *store.App
+	dispatch StageDispatcher
+
+	inputs     []textinput.Model
+	focusIndex int
+	spinner    spinner.Model
+
+	state     orderEntryState
+	err       error
+	order     orderRequest
+	signature string
+}
+
+type orderSubmitMsg struct {
+	signature string
+	err       error
+}
+
+// orderRequest is a validated order entry form
+type orderRequest struct {
+	market        string
+	side          pb.Side
+	orderType     string
+	types         []pb.OrderType
+	price         float64
+	size          float64
+	clientOrderID uint64
+	payer         string
+}
+
+func (r orderRequest) notional() float64 {
+	return r.price * r.size
+}
+
+func (r orderRequest) fee() float64 {
+	if r.orderType == "post" {
+		return 0
+	}
+	return r.notional() * takerFeeRate
+}
+
+func newOrderEntryModel(appStore *store.App) StageModel {
+	m := &orderEntryModel{
+		appStore: appStore,
+		inputs:   make([]textinput.Model, oeInputCount),
+		spinner:  spinner.New(spinner.WithSpinner(spinner.Points)),
+	}
+
+	for i := range m.inputs {
+		t := textinput.New()
+		switch i {
+		case oeMarket:
+			t.Placeholder = "Market Name (e.g. SOL/USDC) or Public Key"
+		case oeSide:
+			t.Placeholder = "Side (bid, ask)"
+		case oeType:
+			t.Placeholder = "Order Type (limit, ioc, post; default limit)"
+		case oePrice:
+			t.Placeholder = "Price"
+		case oeSize:
+			t.Placeholder = "Size"
+		case oeClientOrderID:
+			t.Placeholder = "Client Order ID (optional)"
+			t.Validate = validateClientOrderID
+		case oePayer:
+			t.Placeholder = "Payer (token account to pay from; default public key)"
+		}
+		m.inputs[i] = t
+	}
+	return m
+}
+
+func (m *orderEntryModel) Init(dispatch StageDispatcher) tea.Cmd {
+	m.dispatch = dispatch
+	m.state = oeInput
+	m.focusIndex = 0
+	m.err = nil
+	return tea.Batch(focusInputs(m.inputs, m.focusIndex), textinput.Blink)
+}
+
+func (m *orderEntryModel) Update(msg tea.Msg) (Stage, StageModel, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case orderSubmitMsg:
+		if m.state != oeSubmitting {
+			return StageOrderEntry, m, nil
+		}
+		if msg.err != nil {
+			m.err = msg.err
+			m.state = oePreview
+			return StageOrderEntry, m, nil
+		}
+		m.signature = msg.signature
+		m.state = oeDone
+		return StageOrderEntry, m, nil
+	case spinner.TickMsg:
+		if m.state == oeSubmitting {
+			m.spinner, cmd = m.spinner.Update(msg)
+		}
+		return StageOrderEntry, m, cmd
+	}
+
+	switch m.state {
+	case oeInput:
+		if msg, ok := msg.(marketPickedMsg); ok {
+			m.inputs[oeMarket].SetValue(msg.market)
+			return StageOrderEntry, m, nil
+		}
+		if msg, ok := msg.(tea.KeyMsg); ok && m.focusIndex == oeMarket && key.Matches(msg, keys.PickMarket) {
+			stage, cmd := pickMarket()
+			return stage, m, cmd
+		}
+		cmd, submitted := updateForm(msg, m.inputs, &m.focusIndex)
+		if submitted {
+			m.preview()
+			return StageOrderEntry, m, nil
+		}
+		return StageOrderEntry, m, cmd
+	case oePreview, oeDone:
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch {
+			case m.state == oePreview && key.Matches(msg, keys.Submit):
+				return StageOrderEntry, m, m.submit()
+			case key.Matches(msg, keys.Edit):
+				m.state = oeInput
+				m.err = nil
+				return StageOrderEntry, m, focusInputs(m.inputs, m.focusIndex)
+			}
+		}
+	}
+	return StageOrderEntry, m, nil
+}
+
+// preview validates the form and shows the order for confirmation
+func (m *orderEntryModel) preview() {
+	order, err := m.orderRequest()
+	if err != nil {
+		m.err = err
+		return
+	}
+
+	m.err = nil
+	m.order = order
+	m.state = oePreview
+}
+
+func (m *orderEntryModel) orderRequest() (orderRequest, error) {
+	for _, input := range m.inputs {
+		if input.Err != nil {
+			return orderRequest{}, input.Err
+		}
+	}
+
+	market := strings.TrimSpace(m.inputs[oeMarket].Value())
+	if market == "" {
+		return orderRequest{}, errors.New("market cannot be empty")
+	}
+	side, err := parseSide(m.inputs[oeSide].Value())
+	if err != nil {
+		return orderRequest{}, err
+	}
+	orderType, types, err := parseOrderType(m.inputs[oeType].Value())
+	if err != nil {
+		return orderRequest{}, err
+	}
+	price, err := parseAmount("price", m.inputs[oePrice].Value())
+	if err != nil {
+		return orderRequest{}, err
+	}
+	size, err := parseAmount("size", m.inputs[oeSize].Value())
+	if err != nil {
+		return orderRequest{}, err
+	}
+	clientOrderID, err := parseClientOrderID(m.inputs[oeClientOrderID].Value())
+	if err != nil {
+		return orderRequest{}, err
+	}
+
+	payer := strings.TrimSpace(m.inputs[oePayer].Value())
+	if payer == "" {
+		payer = m.appStore.CurrentSettings().PublicKey.String()
+	}
+
+	return orderRequest{
+		market:        market,
+		side:          side,
+		orderType:     orderType,
+		types:         types,
+		price:         price,
+		size:          size,
+		clientOrderID: clientOrderID,
+		payer:         payer,
+	}, nil
+}
+
+func (m *orderEntryModel) submit() tea.Cmd {
+	settings := m.appStore.CurrentSettings()
+	if settings.PublicKey.IsZero() || len(settings.PrivateKey) == 0 {
+		m.err = errors.New("a private and public key are required to trade: set them from the settings stage")
+		return nil
+	}
+	traderProvider, err := m.appStore.Connected()
+	if err != nil {
+		m.err = err
+		return nil
+	}
+
+	opts := provider.PostOrderOpts{ClientOrderID: m.order.clientOrderID}
+	if !settings.OpenOrdersAddress.IsZero() {
+		opts.OpenOrdersAddress = settings.OpenOrdersAddress.String()
+	}
+
+	m.err = nil
+	m.state = oeSubmitting
+	order := m.order
+	owner := settings.PublicKey.String()
+	dispatch := m.dispatch
+	go func() {
+		ctx, cancel := submitContext()
+		defer cancel()
+		signature, err := traderProvider.SubmitOrder(ctx, owner, order.payer, order.market, order.side, order.types, order.size, order.price, settings.Project, opts)
+		dispatch(orderSubmitMsg{signature: signature, err: submitErr(ctx, err)})
+	}()
+	return m.spinner.Tick
+}
+
+// CapturesKey also holds back while submitting, as leaving would drop the result and signature
+func (m *orderEntryModel) CapturesKey(msg tea.KeyMsg) bool {
+	if m.state == oeSubmitting {
+		return key.Matches(msg, keys.Back)
+	}
+	return m.state == oeInput && m.focusIndex < len(m.inputs) && capturesTextKey(msg)
+}
+
+func (m *orderEntryModel) Busy() bool {
+	return m.state == oeSubmitting
+}
+
+func (m *orderEntryModel) Bindings() []key.Binding {
+	switch m.state {
+	case oePreview:
+		return []key.Binding{keys.Submit, keys.Edit}
+	case oeDone:
+		return []key.Binding{keys.Edit}
+	default:
+		return []key.Binding{keys.NextField, keys.PrevField, keys.Submit, keys.PickMarket}
+	}
+}
+
+func (m *orderEntryModel) View() string {
+	var b strings.Builder
+
+	switch m.state {
+	case oeInput:
+		b.WriteString(inputsView(m.inputs, m.focusIndex))
+	case oePreview, oeSubmitting:
+		b.WriteString(orderPreviewView(m.order))
+		b.WriteString("\n\n")
+		if m.state == oeSubmitting {
+			b.WriteString(m.spinner.View())
+			b.WriteString(" submitting… (back is disabled until the result arrives)\n")
+		} else {
+			b.WriteString(helpStyle.Render(fmt.Sprintf("(%v to sign and submit • %v to edit)", keys.Submit.Help().Key, keys.Edit.Help().Key)))
+			b.WriteRune('\n')
+		}
+	case oeDone:
+		b.WriteString(orderPreviewView(m.order))
+		b.WriteString("\n\n")
+		b.WriteString(statusStyle.Render(fmt.Sprintf("submitted: %v", m.signature)))
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render(fmt.Sprintf("(%v to enter another order)", keys.Edit.Help().Key)))
+		b.WriteRune('\n')
+	}
+
+	if m.err != nil {
+		b.WriteString(errorStyle.Render(m.err.Error()))
+		b.WriteRune('\n')
+	}
+	return b.String()
+}
+
+func orderPreviewView(order orderRequest) string {
+	sideStyle := bidStyle
+	if order.side == pb.Side_S_ASK {
+		sideStyle = askStyle
+	}
+
+	clientOrderID := "-"
+	if order.clientOrderID != 0 {
+		clientOrderID = strconv.FormatUint(order.clientOrderID, 10)
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%v %v %v @ %v in %v\n\n", sideStyle.Render(order.side.String()), order.orderType, formatFloat(order.size), formatFloat(order.price), order.market))
+	b.WriteString(fmt.Sprintf("%-18v %v\n", "notional", formatFloat(order.notional())))
+	b.WriteString(fmt.Sprintf("%-18v %v\n", "est. fee", formatFloat(order.fee())))
+	b.WriteString(fmt.Sprintf("%-18v %v\n", "client order ID", clientOrderID))
+	b.WriteString(fmt.Sprintf("%-18v %v", "payer", order.payer))
+	return b.String()
+}
+
+func parseSide(s string) (pb.Side, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "bid", "buy":
+		return pb.Side_S_BID, nil
+	case "ask", "sell":
+		return pb.Side_S_ASK, nil
+	default:
+		return pb.Side_S_UNKNOWN, fmt.Errorf("invalid side %q: expected bid or ask", s)
+	}
+}
+
+// parseOrderType returns the normalized type name and the order types sent to the API
+func parseOrderType(s string) (string, []pb.OrderType, error) {
+	switch t := strings.ToLower(strings.TrimSpace(s)); t {
+	case "", "limit":
+		return "limit", []pb.OrderType{pb.OrderType_OT_LIMIT}, nil
+	case "ioc":
+		return t, []pb.OrderType{pb.OrderType_OT_LIMIT, pb.OrderType_OT_IOC}, nil
+	case "post", "post-only":
+		return "post", []pb.OrderType{pb.OrderType_OT_LIMIT, pb.OrderType_OT_POST}, nil
+	default:
+		return "", nil, fmt.Errorf("invalid order type %q: expected limit, ioc or post", s)
+	}
+}
+
+func parseAmount(name, s string) (float64, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || f <= 0 || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("invalid %v %q: expected a positive number", name, s)
+	}
+	return f, nil
+}
+
+func validateClientOrderID(s string) error {
+	_, err := parseClientOrderID(s)
+	return err
+}
+
+func parseClientOrderID(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid client order ID %q", s)
+	}
+	return id, nil
+}
@@ -0,0 +1,98 @@
+package program
+
+import (
+	"errors"
+	"github.com/aspin/solana-trader-tui/store"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	tea "github.com/charmbracelet/bubbletea"
+	"testing"
+)
+
+// enterOrder fills in the order entry form and submits it for preview
+func enterOrder(d *stageDriver, market, side, price, size string) {
+	d.keys(market, "tab", side, "tab", "tab", price, "tab", size, "tab", "tab", "tab", "enter")
+}
+
+func TestOrderEntrySubmit(t *testing.T) {
+	fake := store.NewFakeProvider()
+	d := newStageDriver(t, newOrderEntryModel(newTestApp(t, fake)))
+	m := d.model.(*orderEntryModel)
+
+	enterOrder(d, "SOL/USDC", "bid", "10", "2")
+	if m.state != oePreview {
+		t.Fatalf("state = %v, want preview; err = %v", m.state, m.err)
+	}
+
+	d.keys("enter")
+	if !m.CapturesKey(tea.KeyMsg{Type: tea.KeyEsc}) {
+		t.Error("back is not held back while submitting")
+	}
+	d.until("the submission", func() bool {
+		return m.state == oeDone
+	})
+
+	orders := fake.OpenOrders["SOL/USDC"]
+	if len(orders) != 1 {
+		t.Fatalf("%v orders open, want 1", len(orders))
+	}
+	if order := orders[0]; order.Side != pb.Side_S_BID || order.Price != 10 || order.RemainingSize != 2 {
+		t.Errorf("order = %v", order)
+	}
+	if m.signature == "" {
+		t.Error("no signature shown")
+	}
+}
+
+func TestOrderEntrySubmitFailure(t *testing.T) {
+	fake := store.NewFakeProvider()
+	fake.Err = errors.New("insufficient funds")
+	d := newStageDriver(t, newOrderEntryModel(newTestApp(t, fake)))
+	m := d.model.(*orderEntryModel)
+
+	enterOrder(d, "SOL/USDC", "ask", "10", "2")
+	d.keys("enter")
+	d.until("the submission", func() bool {
+		return m.state != oeSubmitting
+	})
+
+	if m.state != oePreview || m.err == nil || m.err.Error() != "insufficient funds" {
+		t.Errorf("state = %v, err = %v; want the preview with the error", m.state, m.err)
+	}
+}
+
+func TestOrderEntryInvalid(t *testing.T) {
+	d := newStageDriver(t, newOrderEntryModel(newTestApp(t, store.NewFakeProvider())))
+	m := d.model.(*orderEntryModel)
+
+	enterOrder(d, "SOL/USDC", "sideways", "10", "2")
+	if m.state != oeInput || m.err == nil {
+		t.Errorf("state = %v, err = %v; want the form with an error", m.state, m.err)
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		s     string
+		want  float64
+		valid bool
+	}{
+		{s: "2", want: 2, valid: true},
+		{s: " 0.5 ", want: 0.5, valid: true},
+		{s: "1e3", want: 1000, valid: true},
+		{s: ""},
+		{s: "0"},
+		{s: "-1"},
+		{s: "abc"},
+		{s: "NaN"},
+		{s: "Inf"},
+		{s: "+Inf"},
+		{s: "-Inf"},
+		{s: "1e400"},
+	}
+	for _, test := range tests {
+		got, err := parseAmount("size", test.s)
+		if (err == nil) != test.valid || got != test.want {
+			t.Errorf("parseAmount(%q) = %v, %v; want %v, valid = %v", test.s, got, err, test.want, test.valid)
+		}
+	}
+}
@@ -1 +1,289 @@
 package program
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aspin/solana-trader-tui/store"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"strconv"
+	"strings"
+)
+
+const defaultOrderbookDepth = 10
+
+var (
+	bidStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#04B575"))
+	askStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("197"))
+)
+
+type orderbookState int
+
+const (
+	obInput orderbookState = iota
+	obLoading
+	obShow
+)
+
+type orderbookModel struct {
+	appStore *store.App
+	dispatch StageDispatcher
+
+	inputs     []textinput.Model
+	focusIndex int
+	spinner    spinner.Model
+
+	state     orderbookState
+	err       error
+	orderbook *pb.GetOrderbookResponse
+}
+
+type orderbookMsg struct {
+	orderbook *pb.GetOrderbookResponse
+	err       error
+}
+
+func newOrderbookModel(appStore *store.App) StageModel {
+	m := &orderbookModel{
+		appStore: appStore,
+		inputs:   make([]textinput.Model, 2),
+		spinner:  spinner.New(spinner.WithSpinner(spinner.Points)),
+	}
+
+	for i := range m.inputs {
+		t := textinput.New()
+		switch i {
+		case 0:
+			t.Placeholder = "Market Name (e.g. SOL/USDC) or Public Key"
+		case 1:
+			t.Placeholder = fmt.Sprintf("Depth (default %v)", defaultOrderbookDepth)
+			t.Validate = validateDepth
+		}
+		m.inputs[i] = t
+	}
+	return m
+}
+
+func (m *orderbookModel) Init(dispatch StageDispatcher) tea.Cmd {
+	m.dispatch = dispatch
+	m.state = obInput
+	m.focusIndex = 0
+	m.err = nil
+	return tea.Batch(focusInputs(m.inputs, m.focusIndex), textinput.Blink)
+}
+
+func (m *orderbookModel) Update(msg tea.Msg) (Stage, StageModel, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case orderbookMsg:
+		if m.state != obLoading {
+			return StageOrderbook, m, nil
+		}
+		if msg.err != nil {
+			m.err = msg.err
+			m.state = obInput
+			return StageOrderbook, m, focusInputs(m.inputs, m.focusIndex)
+		}
+		m.err = nil
+		m.orderbook = msg.orderbook
+		m.state = obShow
+		return StageOrderbook, m, nil
+	case spinner.TickMsg:
+		if m.state == obLoading {
+			m.spinner, cmd = m.spinner.Update(msg)
+		}
+		return StageOrderbook, m, cmd
+	}
+
+	switch m.state {
+	case obInput:
+		if msg, ok := msg.(marketPickedMsg); ok {
+			m.inputs[0].SetValue(msg.market)
+			return StageOrderbook, m, nil
+		}
+		if msg, ok := msg.(tea.KeyMsg); ok && m.focusIndex == 0 && key.Matches(msg, keys.PickMarket) {
+			stage, cmd := pickMarket()
+			return stage, m, cmd
+		}
+		cmd, submitted := updateForm(msg, m.inputs, &m.focusIndex)
+		if submitted {
+			return StageOrderbook, m, m.fetch()
+		}
+		return StageOrderbook, m, cmd
+	case obShow:
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch {
+			case key.Matches(msg, keys.Refresh):
+				return StageOrderbook, m, m.fetch()
+			case key.Matches(msg, keys.Edit):
+				m.state = obInput
+				return StageOrderbook, m, focusInputs(m.inputs, m.focusIndex)
+			}
+		}
+	}
+	return StageOrderbook, m, nil
+}
+
+func (m *orderbookModel) fetch() tea.Cmd {
+	market := strings.TrimSpace(m.inputs[0].Value())
+	if market == "" {
+		m.err = errors.New("market cannot be empty")
+		return nil
+	}
+	if err := m.inputs[1].Err; err != nil {
+		m.err = err
+		return nil
+	}
+	depth := parseDepth(m.inputs[1].Value())
+
+	traderProvider, err := m.appStore.Connected()
+	if err != nil {
+		m.err = err
+		return nil
+	}
+
+	m.err = nil
+	m.state = obLoading
+	project := m.appStore.CurrentSettings().Project
+	timeout := queryTimeout(m.appStore)
+	dispatch := m.dispatch
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		orderbook, err := traderProvider.GetOrderbook(ctx, market, depth, project)
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("orderbook timed out after %v: %w", timeout, err)
+		}
+		dispatch(orderbookMsg{orderbook: orderbook, err: err})
+	}()
+	return m.spinner.Tick
+}
+
+func (m *orderbookModel) CapturesKey(msg tea.KeyMsg) bool {
+	return m.state == obInput && m.focusIndex < len(m.inputs) && capturesTextKey(msg)
+}
+
+// Leave returns a loading fetch to the form: its result is dropped once the stage is left, so it is no longer busy
+func (m *orderbookModel) Leave() {
+	if m.state == obLoading {
+		m.state = obInput
+	}
+}
+
+func (m *orderbookModel) Busy() bool {
+	return m.state == obLoading
+}
+
+func (m *orderbookModel) Bindings() []key.Binding {
+	if m.state == obShow {
+		return []key.Binding{keys.Refresh, keys.Edit}
+	}
+	return []key.Binding{keys.NextField, keys.PrevField, keys.Submit, keys.PickMarket}
+}
+
+func (m *orderbookModel) View() string {
+	var b strings.Builder
+
+	switch m.state {
+	case obInput, obLoading:
+		b.WriteString(inputsView(m.inputs, m.focusIndex))
+		if m.err != nil {
+			b.WriteString(errorStyle.Render(m.err.Error()))
+			b.WriteRune('\n')
+		}
+		if m.state == obLoading {
+			b.WriteString(m.spinner.View())
+		}
+	case obShow:
+		b.WriteString(fmt.Sprintf("%v (%v)\n\n", m.orderbook.Market, m.orderbook.MarketAddress))
+		b.WriteString(ladderView(newLadderLevels(m.orderbook.Bids), newLadderLevels(m.orderbook.Asks)))
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render(fmt.Sprintf("(%v to refresh • %v to change market)", keys.Refresh.Help().Key, keys.Edit.Help().Key)))
+	}
+	return b.String()
+}
+
+func validateDepth(s string) error {
+	if s == "" {
+		return nil
+	}
+	if _, err := strconv.ParseUint(s, 10, 32); err != nil {
+		return fmt.Errorf("invalid depth: %v", s)
+	}
+	return nil
+}
+
+func parseDepth(s string) uint32 {
+	depth, err := strconv.ParseUint(s, 10, 32)
+	if err != nil || depth == 0 {
+		return defaultOrderbookDepth
+	}
+	return uint32(depth)
+}
+
+// ladderLevel is a single price level; changed marks levels to highlight since the last redraw
+type ladderLevel struct {
+	price   float64
+	size    float64
+	changed bool
+}
+
+func newLadderLevels(items []*pb.OrderbookItem) []ladderLevel {
+	levels := make([]ladderLevel, 0, len(items))
+	for _, item := range items {
+		levels = append(levels, ladderLevel{price: item.Price, size: item.Size})
+	}
+	return levels
+}
+
+// ladderView renders bids and asks side by side (best prices first), followed by the spread and mid price
+func ladderView(bids, asks []ladderLevel) string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("%14v %14v │ %-14v %-14v\n", "BID SIZE", "BID", "ASK", "ASK SIZE"))
+	rows := len(bids)
+	if len(asks) > rows {
+		rows = len(asks)
+	}
+	for i := 0; i < rows; i++ {
+		bid := fmt.Sprintf("%14v %14v", "", "")
+		if i < len(bids) {
+			bid = levelStyle(bidStyle, bids[i].changed).Render(fmt.Sprintf("%14v %14v", formatFloat(bids[i].size), formatFloat(bids[i].price)))
+		}
+
+		ask := ""
+		if i < len(asks) {
+			ask = levelStyle(askStyle, asks[i].changed).Render(fmt.Sprintf("%-14v %-14v", formatFloat(asks[i].price), formatFloat(asks[i].size)))
+		}
+		b.WriteString(fmt.Sprintf("%v │ %v\n", bid, ask))
+	}
+
+	b.WriteRune('\n')
+	if len(bids) == 0 || len(asks) == 0 {
+		b.WriteString("spread: - • mid: -")
+		return b.String()
+	}
+	bestBid, bestAsk := bids[0].price, asks[0].price
+	spread := bestAsk - bestBid
+	mid := (bestAsk + bestBid) / 2
+	b.WriteString(fmt.Sprintf("spread: %v (%.2f bps) • mid: %v", formatFloat(spread), spread/mid*10000, formatFloat(mid)))
+	return b.String()
+}
+
+func levelStyle(style lipgloss.Style, changed bool) lipgloss.Style {
+	if changed {
+		return style.Copy().Reverse(true)
+	}
+	return style
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,320 @@
+package program
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aspin/solana-trader-tui/store"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	flashDuration    = 750 * time.Millisecond
+	streamTickPeriod = 250 * time.Millisecond
+)
+
+type orderbookStreamModel struct {
+	appStore *store.App
+	dispatch StageDispatcher
+
+	inputs     []textinput.Model
+	focusIndex int
+
+	streaming bool
+	streamID  int
+	cancel    context.CancelFunc
+	err       error
+
+	market   string
+	slot     int64
+	updates  int
+	received []time.Time
+	book     localBook
+}
+
+type orderbookStreamMsg struct {
+	streamID int
+	update   *pb.GetOrderbooksStreamResponse
+}
+
+type orderbookStreamErrMsg struct {
+	streamID int
+	err      error
+}
+
+type orderbookStreamTickMsg struct {
+	streamID int
+}
+
+func newOrderbookStreamModel(appStore *store.App) StageModel {
+	m := &orderbookStreamModel{
+		appStore: appStore,
+		inputs:   make([]textinput.Model, 2),
+	}
+
+	for i := range m.inputs {
+		t := textinput.New()
+		switch i {
+		case 0:
+			t.Placeholder = "Market Name (e.g. SOL/USDC) or Public Key"
+		case 1:
+			t.Placeholder = fmt.Sprintf("Depth (default %v)", defaultOrderbookDepth)
+			t.Validate = validateDepth
+		}
+		m.inputs[i] = t
+	}
+	return m
+}
+
+func (m *orderbookStreamModel) Init(dispatch StageDispatcher) tea.Cmd {
+	m.dispatch = dispatch
+	m.focusIndex = 0
+	m.err = nil
+	return tea.Batch(focusInputs(m.inputs, m.focusIndex), textinput.Blink)
+}
+
+func (m *orderbookStreamModel) Update(msg tea.Msg) (Stage, StageModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case orderbookStreamMsg:
+		if msg.streamID == m.streamID && m.streaming {
+			m.apply(msg.update)
+		}
+		return StageStream, m, nil
+	case orderbookStreamErrMsg:
+		if msg.streamID == m.streamID && m.streaming {
+			m.stop()
+			m.err = msg.err
+			return StageStream, m, focusInputs(m.inputs, m.focusIndex)
+		}
+		return StageStream, m, nil
+	case orderbookStreamTickMsg:
+		if msg.streamID != m.streamID || !m.streaming {
+			return StageStream, m, nil
+		}
+		m.trimReceived(time.Now())
+		return StageStream, m, m.tick()
+	}
+
+	if m.streaming {
+		if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, keys.Edit) {
+			m.stop()
+			return StageStream, m, focusInputs(m.inputs, m.focusIndex)
+		}
+		return StageStream, m, nil
+	}
+
+	if msg, ok := msg.(marketPickedMsg); ok {
+		m.inputs[0].SetValue(msg.market)
+		return StageStream, m, nil
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok && m.focusIndex == 0 && key.Matches(msg, keys.PickMarket) {
+		stage, cmd := pickMarket()
+		return stage, m, cmd
+	}
+	cmd, submitted := updateForm(msg, m.inputs, &m.focusIndex)
+	if submitted {
+		return StageStream, m, m.start()
+	}
+	return StageStream, m, cmd
+}
+
+func (m *orderbookStreamModel) start() tea.Cmd {
+	market := strings.TrimSpace(m.inputs[0].Value())
+	if market == "" {
+		m.err = errors.New("market cannot be empty")
+		return nil
+	}
+	if err := m.inputs[1].Err; err != nil {
+		m.err = err
+		return nil
+	}
+	depth := parseDepth(m.inputs[1].Value())
+
+	provider, err := m.appStore.Connected()
+	if err != nil {
+		m.err = err
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.streamID++
+	m.cancel = cancel
+	m.streaming = true
+	m.err = nil
+	m.market = market
+	m.slot = 0
+	m.updates = 0
+	m.received = nil
+	m.book = newLocalBook()
+
+	streamID := m.streamID
+	project := m.appStore.CurrentSettings().Project
+	dispatch := m.dispatch
+	go func() {
+		stream, err := provider.GetOrderbookStream(ctx, []string{market}, depth, project)
+		if err != nil {
+			dispatch(orderbookStreamErrMsg{streamID: streamID, err: err})
+			return
+		}
+
+		for {
+			update, err := stream()
+			if err != nil {
+				if ctx.Err() == nil {
+					dispatch(orderbookStreamErrMsg{streamID: streamID, err: err})
+				}
+				return
+			}
+			dispatch(orderbookStreamMsg{streamID: streamID, update: update})
+		}
+	}()
+	return m.tick()
+}
+
+// stop cancels the stream's context, ending the receiving goroutine
+func (m *orderbookStreamModel) stop() {
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+	m.streaming = false
+}
+
+func (m *orderbookStreamModel) tick() tea.Cmd {
+	streamID := m.streamID
+	return tea.Tick(streamTickPeriod, func(time.Time) tea.Msg {
+		return orderbookStreamTickMsg{streamID: streamID}
+	})
+}
+
+func (m *orderbookStreamModel) apply(update *pb.GetOrderbooksStreamResponse) {
+	now := time.Now()
+	m.updates++
+	m.slot = update.Slot
+	m.received = append(m.received, now)
+	m.trimReceived(now)
+
+	if update.Orderbook != nil {
+		m.book.apply(update.Orderbook, now)
+	}
+}
+
+// trimReceived keeps only the arrival times within the last second, so its length is the message rate
+func (m *orderbookStreamModel) trimReceived(now time.Time) {
+	i := 0
+	for i < len(m.received) && now.Sub(m.received[i]) > time.Second {
+		i++
+	}
+	m.received = m.received[i:]
+}
+
+func (m *orderbookStreamModel) Leave() {
+	m.stop()
+}
+
+func (m *orderbookStreamModel) CapturesKey(msg tea.KeyMsg) bool {
+	return !m.streaming && m.focusIndex < len(m.inputs) && capturesTextKey(msg)
+}
+
+func (m *orderbookStreamModel) Busy() bool {
+	return m.streaming
+}
+
+func (m *orderbookStreamModel) Bindings() []key.Binding {
+	if m.streaming {
+		return []key.Binding{keys.Edit}
+	}
+	return []key.Binding{keys.NextField, keys.PrevField, keys.Submit, keys.PickMarket}
+}
+
+func (m *orderbookStreamModel) View() string {
+	var b strings.Builder
+
+	if !m.streaming {
+		b.WriteString(inputsView(m.inputs, m.focusIndex))
+		if m.err != nil {
+			b.WriteString(errorStyle.Render(m.err.Error()))
+			b.WriteRune('\n')
+		}
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("%v • slot %v • %v updates • %v msg/s\n\n", m.market, m.slot, m.updates, len(m.received)))
+	now := time.Now()
+	b.WriteString(ladderView(m.book.levels(m.book.bids, m.book.bidFlash, true, now), m.book.levels(m.book.asks, m.book.askFlash, false, now)))
+	b.WriteString("\n\n")
+	b.WriteString(helpStyle.Render(fmt.Sprintf("(%v to stop and change market)", keys.Edit.Help().Key)))
+	return b.String()
+}
+
+// localBook is the orderbook as of the latest stream update, remembering when each price level last changed
+type localBook struct {
+	bids     map[float64]float64
+	asks     map[float64]float64
+	bidFlash map[float64]time.Time
+	askFlash map[float64]time.Time
+
+	// initialized is set after the first update, which is not flashed
+	initialized bool
+}
+
+func newLocalBook() localBook {
+	return localBook{
+		bids:     make(map[float64]float64),
+		asks:     make(map[float64]float64),
+		bidFlash: make(map[float64]time.Time),
+		askFlash: make(map[float64]time.Time),
+	}
+}
+
+func (b *localBook) apply(orderbook *pb.GetOrderbookResponse, now time.Time) {
+	b.bids = applySide(b.bids, b.bidFlash, orderbook.Bids, b.initialized, now)
+	b.asks = applySide(b.asks, b.askFlash, orderbook.Asks, b.initialized, now)
+	b.initialized = true
+}
+
+// applySide replaces a side with the update's levels, flashing levels whose size changed and forgetting removed ones
+func applySide(side map[float64]float64, flash map[float64]time.Time, items []*pb.OrderbookItem, flashChanges bool, now time.Time) map[float64]float64 {
+	next := make(map[float64]float64, len(items))
+	for _, item := range items {
+		if item.Size == 0 {
+			continue
+		}
+		next[item.Price] = item.Size
+		if size, ok := side[item.Price]; flashChanges && (!ok || size != item.Size) {
+			flash[item.Price] = now
+		}
+	}
+
+	for price := range flash {
+		if _, ok := next[price]; !ok {
+			delete(flash, price)
+		}
+	}
+	return next
+}
+
+func (b localBook) levels(side map[float64]float64, flash map[float64]time.Time, descending bool, now time.Time) []ladderLevel {
+	levels := make([]ladderLevel, 0, len(side))
+	for price, size := range side {
+		levels = append(levels, ladderLevel{
+			price:   price,
+			size:    size,
+			changed: now.Sub(flash[price]) < flashDuration,
+		})
+	}
+
+	sort.Slice(levels, func(i, j int) bool {
+		if descending {
+			return levels[i].price > levels[j].price
+		}
+		return levels[i].price < levels[j].price
+	})
+	return levels
+}
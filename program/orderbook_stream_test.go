@@ -0,0 +1,66 @@
+package program
+
+import (
+	"github.com/aspin/solana-trader-tui/store"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"testing"
+	"time"
+)
+
+func TestLocalBookFlashesChanges(t *testing.T) {
+	now := time.Now()
+	book := newLocalBook()
+	book.apply(&pb.GetOrderbookResponse{
+		Bids: []*pb.OrderbookItem{{Price: 10, Size: 1}, {Price: 9, Size: 2}},
+		Asks: []*pb.OrderbookItem{{Price: 11, Size: 1}},
+	}, now)
+	for _, level := range book.levels(book.bids, book.bidFlash, true, now) {
+		if level.changed {
+			t.Errorf("bid %v flashed on the first update", level.price)
+		}
+	}
+
+	book.apply(&pb.GetOrderbookResponse{
+		Bids: []*pb.OrderbookItem{{Price: 10, Size: 3}, {Price: 9, Size: 0}},
+		Asks: []*pb.OrderbookItem{{Price: 11, Size: 1}},
+	}, now)
+	bids := book.levels(book.bids, book.bidFlash, true, now)
+	if len(bids) != 1 || bids[0].price != 10 || bids[0].size != 3 || !bids[0].changed {
+		t.Errorf("bids = %+v, want only the resized level at 10, flashed", bids)
+	}
+	if asks := book.levels(book.asks, book.askFlash, false, now); len(asks) != 1 || asks[0].changed {
+		t.Errorf("asks = %+v, want the unchanged level not flashed", asks)
+	}
+	if bids := book.levels(book.bids, book.bidFlash, true, now.Add(flashDuration)); bids[0].changed {
+		t.Errorf("bid still flashed after %v", flashDuration)
+	}
+}
+
+func TestOrderbookStreamUpdates(t *testing.T) {
+	defer func(interval time.Duration) {
+		store.FakeStreamInterval = interval
+	}(store.FakeStreamInterval)
+	store.FakeStreamInterval = 10 * time.Millisecond
+
+	fake := store.NewFakeProvider()
+	fake.Orderbooks["SOL/USDC"] = &pb.GetOrderbookResponse{
+		Market: "SOL/USDC",
+		Bids:   []*pb.OrderbookItem{{Price: 10, Size: 1}},
+		Asks:   []*pb.OrderbookItem{{Price: 11, Size: 1}},
+	}
+	d := newStageDriver(t, newOrderbookStreamModel(newTestApp(t, fake)))
+	m := d.model.(*orderbookStreamModel)
+
+	d.keys("SOL/USDC", "enter", "enter", "enter")
+	d.until("a few stream updates", func() bool {
+		return m.updates >= 3
+	})
+	if !m.Busy() || len(m.book.bids) != 1 {
+		t.Errorf("busy = %v, bids = %v while streaming", m.Busy(), m.book.bids)
+	}
+
+	d.keys("e")
+	if m.streaming || m.Busy() {
+		t.Error("still streaming after stopping")
+	}
+}
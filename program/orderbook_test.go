@@ -0,0 +1,68 @@
+package program
+
+import (
+	"context"
+	"github.com/aspin/solana-trader-tui/store"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOrderbookFetch(t *testing.T) {
+	fake := store.NewFakeProvider()
+	fake.Orderbooks["SOL/USDC"] = &pb.GetOrderbookResponse{
+		Market: "SOL/USDC",
+		Bids:   []*pb.OrderbookItem{{Price: 10, Size: 1}, {Price: 9, Size: 2}},
+		Asks:   []*pb.OrderbookItem{{Price: 11, Size: 1}, {Price: 12, Size: 2}},
+	}
+	d := newStageDriver(t, newOrderbookModel(newTestApp(t, fake)))
+	m := d.model.(*orderbookModel)
+
+	d.keys("SOL/USDC", "tab", "1", "enter", "enter")
+	d.until("the orderbook", func() bool {
+		return m.state == obShow
+	})
+	if n := len(m.orderbook.Bids); n != 1 {
+		t.Errorf("%v bids, want the depth of 1", n)
+	}
+}
+
+// hangingOrderbook never responds with an orderbook, until the context finishes
+type hangingOrderbook struct {
+	*store.FakeProvider
+}
+
+func (p hangingOrderbook) GetOrderbook(ctx context.Context, market string, limit uint32, project pb.Project) (*pb.GetOrderbookResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestOrderbookTimeout(t *testing.T) {
+	appStore := newTestApp(t, hangingOrderbook{store.NewFakeProvider()})
+	appStore.QueryTimeout = 50 * time.Millisecond
+	d := newStageDriver(t, newOrderbookModel(appStore))
+	m := d.model.(*orderbookModel)
+
+	d.keys("SOL/USDC", "enter", "enter", "enter")
+	d.until("the orderbook to time out", func() bool {
+		return m.state == obInput && m.err != nil
+	})
+	if !strings.Contains(m.err.Error(), "timed out after 50ms") {
+		t.Errorf("err = %v, want the timeout reported", m.err)
+	}
+}
+
+func TestOrderbookLeaveWhileLoading(t *testing.T) {
+	d := newStageDriver(t, newOrderbookModel(newTestApp(t, hangingOrderbook{store.NewFakeProvider()})))
+	m := d.model.(*orderbookModel)
+
+	d.keys("SOL/USDC", "enter", "enter", "enter")
+	if !m.Busy() {
+		t.Fatal("not busy while loading")
+	}
+	m.Leave()
+	if m.Busy() {
+		t.Error("still busy after leaving, so quitting from another stage asks to confirm")
+	}
+}
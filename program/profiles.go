@@ -0,0 +1,220 @@
+package program
+
+import (
+	"fmt"
+	"github.com/aspin/solana-trader-tui/store"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"strings"
+)
+
+type profilesModel struct {
+	err    error
+	status string
+
+	appStore *store.App
+	dispatch StageDispatcher
+
+	list      list.Model
+	nameInput textinput.Model
+	adding    bool
+}
+
+type profileItem struct {
+	summary store.ProfileSummary
+}
+
+func (i profileItem) Title() string {
+	if i.summary.Active {
+		return fmt.Sprintf("%v (active)", i.summary.Name)
+	}
+	return i.summary.Name
+}
+
+func (i profileItem) Description() string {
+	if i.summary.Locked {
+		return fmt.Sprintf("%v; private key locked", i.summary.Environment)
+	}
+	return i.summary.Environment
+}
+
+func (i profileItem) FilterValue() string {
+	return i.summary.Name
+}
+
+func newProfilesModel(appStore *store.App) StageModel {
+	nameInput := textinput.New()
+	nameInput.Placeholder = "New Profile Name"
+
+	m := &profilesModel{
+		appStore:  appStore,
+		list:      list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0),
+		nameInput: nameInput,
+	}
+	m.list.SetShowTitle(false)
+	return m
+}
+
+func (m *profilesModel) Init(dispatch StageDispatcher) tea.Cmd {
+	m.dispatch = dispatch
+	m.err = nil
+	m.status = ""
+	m.adding = false
+	m.loadItems()
+	m.setSize()
+	return nil
+}
+
+func (m *profilesModel) loadItems() {
+	profiles := m.appStore.Profiles()
+	items := make([]list.Item, 0, len(profiles))
+	for _, profile := range profiles {
+		items = append(items, profileItem{summary: profile})
+	}
+	m.list.SetItems(items)
+}
+
+func (m *profilesModel) setSize() {
+	h, v := listStyle.GetFrameSize()
+	m.list.SetSize(m.appStore.UI.WindowWidth-h, m.appStore.UI.WindowHeight-v-4)
+}
+
+func (m *profilesModel) Update(msg tea.Msg) (Stage, StageModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.setSize()
+	case statusMsg:
+		m.status = msg.status
+		return StageProfiles, m, nil
+	case statusErrMsg:
+		m.status = ""
+		m.err = msg.err
+		return StageProfiles, m, nil
+	case statusDoneMsg:
+		return StageMenu, m, nil
+	case tea.KeyMsg:
+		if m.adding {
+			return m.updateAdding(msg)
+		}
+		if m.list.FilterState() == list.Filtering {
+			break
+		}
+
+		switch {
+		case key.Matches(msg, keys.Select):
+			item, ok := m.list.SelectedItem().(profileItem)
+			if !ok {
+				return StageProfiles, m, nil
+			}
+			return m.switchProfile(item.summary.Name)
+		case key.Matches(msg, keys.NewProfile):
+			m.adding = true
+			m.err = nil
+			m.nameInput.SetValue("")
+			m.nameInput.PromptStyle = focusedStyle
+			return StageProfiles, m, m.nameInput.Focus()
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return StageProfiles, m, cmd
+}
+
+func (m *profilesModel) CapturesKey(msg tea.KeyMsg) bool {
+	return m.adding || m.list.FilterState() != list.Unfiltered || capturesDisconnectedBack(m.appStore, msg)
+}
+
+func (m *profilesModel) Bindings() []key.Binding {
+	if m.adding {
+		return []key.Binding{keys.Submit, keys.Cancel}
+	}
+	return []key.Binding{keys.Select, keys.NewProfile, m.list.KeyMap.CursorUp, m.list.KeyMap.CursorDown, m.list.KeyMap.Filter}
+}
+
+func (m *profilesModel) updateAdding(msg tea.KeyMsg) (Stage, StageModel, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Cancel):
+		m.adding = false
+		m.nameInput.Blur()
+		return StageProfiles, m, nil
+	case key.Matches(msg, keys.Submit):
+		name := strings.TrimSpace(m.nameInput.Value())
+		if err := m.appStore.AddProfile(name); err != nil {
+			m.err = err
+			return StageProfiles, m, nil
+		}
+
+		m.adding = false
+		m.nameInput.Blur()
+		return m.switchProfile(name)
+	}
+
+	var cmd tea.Cmd
+	m.nameInput, cmd = m.nameInput.Update(msg)
+	return StageProfiles, m, cmd
+}
+
+func (m *profilesModel) switchProfile(name string) (Stage, StageModel, tea.Cmd) {
+	m.err = nil
+	if err := m.appStore.SwitchProfile(name); err != nil {
+		m.err = err
+		return StageProfiles, m, nil
+	}
+	if err := m.appStore.Save(); err != nil {
+		m.err = fmt.Errorf("could not save active profile: %w", err)
+		return StageProfiles, m, nil
+	}
+
+	if m.appStore.NeedsUnlock() {
+		return StageUnlock, m, nil
+	}
+	if m.appStore.NeedsInit() {
+		return StageSettings, m, nil
+	}
+
+	m.loadItems()
+	dispatch := m.dispatch
+	go func() {
+		dispatch(statusMsg{status: fmt.Sprintf("connecting with profile %v...", name)})
+		err := m.appStore.Connect()
+		if err != nil {
+			dispatch(statusErrMsg{err: err})
+			return
+		}
+		dispatch(statusDoneMsg{})
+	}()
+	return StageProfiles, m, nil
+}
+
+func (m *profilesModel) View() string {
+	var b strings.Builder
+
+	if m.adding {
+		b.WriteString(m.nameInput.View())
+		b.WriteString("\n\n")
+	} else {
+		b.WriteString(listStyle.Render(m.list.View()))
+		b.WriteRune('\n')
+	}
+
+	if m.status != "" {
+		b.WriteString(statusStyle.Render(m.status))
+		b.WriteRune('\n')
+	}
+
+	if m.err != nil {
+		b.WriteString(errorStyle.Render(m.err.Error()))
+		b.WriteRune('\n')
+	}
+
+	b.WriteString(disconnectedView(m.appStore))
+	if m.adding {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("(%v to create profile • %v to cancel)", keys.Submit.Help().Key, keys.Cancel.Help().Key)))
+	} else {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("(%v to switch profile • %v to add a profile)", keys.Select.Help().Key, keys.NewProfile.Help().Key)))
+	}
+	return b.String()
+}
@@ -0,0 +1,37 @@
+package program
+
+import (
+	"github.com/aspin/solana-trader-tui/store"
+	tea "github.com/charmbracelet/bubbletea"
+	"strings"
+	"testing"
+)
+
+func TestProfilesNewProfileBlocksBack(t *testing.T) {
+	appStore := newTestApp(t, store.NewFakeProvider())
+	d := newStageDriver(t, newProfilesModel(appStore))
+
+	d.keys("n", "trading")
+	stage, _, _ := d.model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if stage != StageSettings {
+		t.Fatalf("stage = %v after adding a profile, want settings", stage)
+	}
+
+	esc := tea.KeyMsg{Type: tea.KeyEsc}
+	for _, model := range []StageModel{newSettingsModel(appStore), newUnlockModel(appStore), d.model} {
+		if !model.(KeyCapturer).CapturesKey(esc) {
+			t.Errorf("%T lets esc go back while disconnected", model)
+		}
+	}
+}
+
+func TestOpenOrdersDisconnected(t *testing.T) {
+	appStore := newTestApp(t, nil)
+	d := newStageDriver(t, newOpenOrdersModel(appStore))
+	m := d.model.(*openOrdersModel)
+
+	d.keys("SOL/USDC", "enter", "enter")
+	d.until("the not connected error", func() bool {
+		return strings.Contains(m.View(), store.ErrNotConnected.Error())
+	})
+}
@@ -2,40 +2,86 @@ package program
 
 import (
 	"fmt"
+	"github.com/aspin/solana-trader-tui/keymap"
 	"github.com/aspin/solana-trader-tui/store"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"log"
 	"strings"
 )
 
+var (
+	confirmStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+
+	// keys is the global keymap shared by all stages, loaded from the config file in New
+	keys = keymap.Default()
+)
+
 type appModel struct {
 	stage    Stage
+	history  []Stage
 	models   map[Stage]StageModel
 	store    *store.App
 	dispatch StageDispatcher
+
+	// requests is the current request of each stage, started each time the stage is entered
+	requests map[Stage]int
+
+	// notifications watches order statuses in the background and shows them as toasts over every stage
+	notifications *notificationsModel
+
+	confirmingQuit bool
+	showHelp       bool
+	help           help.Model
 }
 
 func New(s *store.App) *tea.Program {
+	var err error
+	keys, err = keymap.New(s.Keys)
+	if err != nil {
+		log.Printf("skipped invalid key bindings, which keep their defaults: %v", err)
+	}
+
 	initialStage := StageMenu
-	if s.NeedsInit() {
+	if s.NeedsUnlock() {
+		initialStage = StageUnlock
+	} else if s.NeedsInit() {
 		initialStage = StageSettings
 	} else {
-		err := s.Connect()
+		err = s.Connect()
 		if err != nil {
 			s.Err = fmt.Errorf("bad configuration: could not connect API client: %v", err)
 		}
 	}
 
 	m := &appModel{
-		stage: initialStage,
-		store: s,
+		stage:         initialStage,
+		store:         s,
+		help:          help.New(),
+		requests:      make(map[Stage]int),
+		notifications: newNotificationsModel(s),
 	}
 
 	models := map[Stage]StageModel{
-		StageSettings:   newSettingsModel(m.store),
-		StageMenu:       newMenuModel(m.store),
-		StageError:      newErrorModel(m.store),
-		StageOpenOrders: newOpenOrdersModel(m.store),
+		StageSettings:      newSettingsModel(m.store),
+		StageMenu:          newMenuModel(m.store),
+		StageError:         newErrorModel(m.store),
+		StageOpenOrders:    newOpenOrdersModel(m.store),
+		StageUnlock:        newUnlockModel(m.store),
+		StageProfiles:      newProfilesModel(m.store),
+		StageOrderbook:     newOrderbookModel(m.store),
+		StageStream:        newOrderbookStreamModel(m.store),
+		StageOrderEntry:    newOrderEntryModel(m.store),
+		StageReplaceOrder:  newReplaceOrderModel(m.store),
+		StageSettle:        newSettleModel(m.store),
+		StageBalances:      newBalancesModel(m.store),
+		StageMarkets:       newMarketsModel(m.store),
+		StageTickers:       newTickersModel(m.store),
+		StageTimeSales:     newTimeSalesModel(m.store),
+		StageSwap:          newSwapModel(m.store),
+		StageNotifications: m.notifications,
 	}
 	m.models = models
 
@@ -45,15 +91,62 @@ func New(s *store.App) *tea.Program {
 }
 
 func (m appModel) Init() tea.Cmd {
-	return m.models[m.stage].Init(m.dispatch)
+	return m.models[m.stage].Init(m.dispatcher(m.stage))
+}
+
+// dispatcher starts a new request for stage, whose messages are delivered until the stage is left or entered again
+func (m appModel) dispatcher(stage Stage) StageDispatcher {
+	m.requests[stage]++
+	request := m.requests[stage]
+	return func(msg tea.Msg) {
+		m.dispatch(envelope{stage: stage, request: request, msg: msg})
+	}
 }
 
 func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// follow connection, profile and favorite changes made by any stage
+	m.notifications.watch(m.dispatch)
+
+	if e, ok := msg.(envelope); ok {
+		// drop results of requests made by a stage that has since been left or re-entered
+		if e.stage != m.stage || e.request != m.requests[e.stage] {
+			return m, nil
+		}
+		msg = e.msg
+	}
+
 	switch msg := msg.(type) {
+	case orderStatusMsg, orderStatusErrMsg:
+		return m, m.notifications.handle(msg)
+	case toastExpiredMsg:
+		// nothing to update; the toast is no longer rendered once expired
+		return m, nil
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyCtrlC, tea.KeyEsc:
-			return m, tea.Quit
+		if m.confirmingQuit {
+			m.confirmingQuit = false
+			if key.Matches(msg, keys.Confirm) {
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+
+		if key.Matches(msg, keys.ForceQuit) {
+			return m.quit()
+		}
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+		if !m.capturesKey(msg) {
+			switch {
+			case key.Matches(msg, keys.Quit):
+				return m.quit()
+			case key.Matches(msg, keys.Back):
+				return m.transition(StageBack)
+			case key.Matches(msg, keys.Help):
+				m.showHelp = true
+				return m, nil
+			}
 		}
 	case tea.WindowSizeMsg:
 		m.store.UI.WindowWidth = msg.Width
@@ -78,19 +171,91 @@ func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nextCmd
 	}
 
+	next, cmd := m.transition(nextStage)
+	return next, tea.Batch(nextCmd, cmd)
+}
+
+// transition moves onto the next stage, recording the current one in the history. Going back pops the history,
+// and returning to the menu or to a stage already in the history unwinds it.
+func (m appModel) transition(nextStage Stage) (tea.Model, tea.Cmd) {
+	if nextStage == StageBack {
+		if len(m.history) == 0 {
+			return m, nil
+		}
+		nextStage = m.history[len(m.history)-1]
+		m.history = m.history[:len(m.history)-1]
+	} else if nextStage == StageMenu {
+		m.history = nil
+	} else if i := m.historyIndex(nextStage); i >= 0 {
+		m.history = m.history[:i]
+	} else {
+		m.history = append(m.history, m.stage)
+	}
+
 	// stage transition: move onto initializing next model
-	nextModel, ok = m.models[nextStage]
+	nextModel, ok := m.models[nextStage]
 	if !ok {
-		log.Printf("error[update]: could not find model for next stage %v", m.stage)
+		log.Printf("error[update]: could not find model for next stage %v", nextStage)
 		return m, tea.Quit
 	}
+	if leaver, ok := m.models[m.stage].(Leaver); ok {
+		leaver.Leave()
+	}
 	m.stage = nextStage
-	return m, nextModel.Init(m.dispatch)
+	return m, nextModel.Init(m.dispatcher(nextStage))
+}
+
+func (m appModel) historyIndex(stage Stage) int {
+	for i, s := range m.history {
+		if s == stage {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m appModel) capturesKey(msg tea.KeyMsg) bool {
+	capturer, ok := m.models[m.stage].(KeyCapturer)
+	return ok && capturer.CapturesKey(msg)
+}
+
+// helpView lists the stage's active bindings followed by the global ones
+func (m appModel) helpView(model StageModel) string {
+	groups := make([][]key.Binding, 0, 2)
+	if provider, ok := model.(HelpProvider); ok {
+		groups = append(groups, provider.Bindings())
+	}
+	groups = append(groups, keys.Global())
+
+	m.help.Width = m.store.UI.WindowWidth
+	return m.help.FullHelpView(groups) + "\n\n" + helpStyle.Render("(press any key to close help)")
+}
+
+// quit exits immediately unless a stage has work in progress, in which case confirmation is requested first. Every stage
+// is checked, as work such as submissions keeps running after its stage is left, as do the order status streams.
+func (m appModel) quit() (tea.Model, tea.Cmd) {
+	for _, model := range m.models {
+		if reporter, ok := model.(BusyReporter); ok && reporter.Busy() {
+			m.confirmingQuit = true
+			return m, nil
+		}
+	}
+	return m, tea.Quit
 }
 
 func (m appModel) View() string {
 	var b strings.Builder
-	b.WriteString("bloXroute Trader API\n\n")
+	b.WriteString("bloXroute Trader API ")
+	b.WriteString(helpStyle.Render(fmt.Sprintf("[%v: %v]", m.store.Profile, m.store.CurrentSettings().Environment())))
+	b.WriteString("\n\n")
+
+	// toasts go above the stage so they are not pushed off screen by full height lists
+	if m.stage != StageNotifications {
+		if toasts := m.notifications.toastsView(); toasts != "" {
+			b.WriteString(toasts)
+			b.WriteRune('\n')
+		}
+	}
 
 	model, ok := m.models[m.stage]
 	if !ok {
@@ -101,6 +266,15 @@ func (m appModel) View() string {
 		model = m.models[StageError]
 	}
 
-	b.WriteString(model.View())
+	if m.showHelp {
+		b.WriteString(m.helpView(model))
+	} else {
+		b.WriteString(model.View())
+	}
+
+	if m.confirmingQuit {
+		b.WriteString("\n\n")
+		b.WriteString(confirmStyle.Render(fmt.Sprintf("Requests or streams are still active. Quit anyway? (%v to quit, any other key to stay)", keys.Confirm.Help().Key)))
+	}
 	return b.String()
 }
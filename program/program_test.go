@@ -0,0 +1,145 @@
+package program
+
+import (
+	"fmt"
+	"github.com/aspin/solana-trader-tui/keymap"
+	tea "github.com/charmbracelet/bubbletea"
+	"testing"
+)
+
+// stubStage is a stage with nothing to show, reporting busy as set and keeping the messages it receives
+type stubStage struct {
+	stage    Stage
+	busy     bool
+	dispatch StageDispatcher
+	received []tea.Msg
+}
+
+func (s *stubStage) Init(dispatch StageDispatcher) tea.Cmd {
+	s.dispatch = dispatch
+	return nil
+}
+
+func (s *stubStage) Update(msg tea.Msg) (Stage, StageModel, tea.Cmd) {
+	s.received = append(s.received, msg)
+	return s.stage, s, nil
+}
+
+func (s *stubStage) View() string {
+	return ""
+}
+
+func (s *stubStage) Busy() bool {
+	return s.busy
+}
+
+func newTestAppModel(stages ...Stage) appModel {
+	m := appModel{
+		stage:    stages[0],
+		models:   make(map[Stage]StageModel),
+		requests: make(map[Stage]int),
+		dispatch: func(tea.Msg) {},
+	}
+	for _, stage := range stages {
+		m.models[stage] = &stubStage{stage: stage}
+	}
+	return m
+}
+
+func TestAppModelHistory(t *testing.T) {
+	m := newTestAppModel(StageMenu, StageOpenOrders, StageReplaceOrder, StageSettle)
+
+	for _, next := range []Stage{StageOpenOrders, StageReplaceOrder, StageSettle} {
+		model, _ := m.transition(next)
+		m = model.(appModel)
+	}
+	model, _ := m.transition(StageBack)
+	m = model.(appModel)
+	if m.stage != StageReplaceOrder {
+		t.Fatalf("stage = %v after going back, want the previous stage", m.stage)
+	}
+
+	model, _ = m.transition(StageOpenOrders)
+	m = model.(appModel)
+	if len(m.history) != 1 || m.history[0] != StageMenu {
+		t.Errorf("history = %v after returning to open orders, want it unwound to the menu", m.history)
+	}
+}
+
+func TestAppModelDropsStaleResults(t *testing.T) {
+	var sent []tea.Msg
+	m := newTestAppModel(StageMenu, StageSettle)
+	m.notifications = newNotificationsModel(newTestApp(t, nil))
+	m.dispatch = func(msg tea.Msg) {
+		sent = append(sent, msg)
+	}
+	settle := m.models[StageSettle].(*stubStage)
+	deliver := func(msg tea.Msg) {
+		settle.dispatch(msg)
+		for _, msg := range sent {
+			model, _ := m.Update(msg)
+			m = model.(appModel)
+		}
+		sent = nil
+	}
+
+	model, _ := m.transition(StageSettle)
+	m = model.(appModel)
+	first := settle.dispatch
+	deliver("current")
+
+	model, _ = m.transition(StageBack)
+	m = model.(appModel)
+	deliver("after leaving")
+
+	model, _ = m.transition(StageSettle)
+	m = model.(appModel)
+	second := settle.dispatch
+	settle.dispatch = first
+	deliver("overtaken")
+	settle.dispatch = second
+	deliver("re-entered")
+
+	if got := fmt.Sprint(settle.received); got != "[current re-entered]" {
+		t.Errorf("settle received %v, want only the results of its current request", got)
+	}
+}
+
+func TestAppModelQuit(t *testing.T) {
+	m := newTestAppModel(StageMenu, StageSettle)
+
+	model, cmd := m.quit()
+	if model.(appModel).confirmingQuit || cmd == nil {
+		t.Fatal("quitting with nothing in progress asked for confirmation")
+	}
+
+	m.models[StageSettle].(*stubStage).busy = true
+	model, cmd = m.quit()
+	if !model.(appModel).confirmingQuit || cmd != nil {
+		t.Error("quitting with a busy stage in the background did not ask for confirmation")
+	}
+}
+
+func TestAppModelConfirmQuitKey(t *testing.T) {
+	defer func(k keymap.KeyMap) {
+		keys = k
+	}(keys)
+	var err error
+	if keys, err = keymap.New(map[string][]string{"confirm": {"o"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		key  string
+		quit bool
+	}{{key: "y"}, {key: "o", quit: true}} {
+		m := newTestAppModel(StageMenu)
+		m.notifications = newNotificationsModel(newTestApp(t, nil))
+		m.confirmingQuit = true
+
+		_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(test.key)})
+		if quit := cmd != nil && cmd() == tea.Quit(); quit != test.quit {
+			t.Errorf("%q quit = %v, want %v", test.key, quit, test.quit)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package program
+
+import (
+	"errors"
+	"fmt"
+	"github.com/aspin/solana-trader-tui/store"
+	"github.com/bloXroute-Labs/solana-trader-client-go/provider"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"strings"
+)
+
+// replace order input indexes
+const (
+	roPrice = iota
+	roSize
+	roPayer
+	roInputCount
+)
+
+// replaceOrderModel amends the price and size of an open order, keeping its side, type and client order ID
+type replaceOrderModel struct {
+	appStore *store.App
+	dispatch StageDispatcher
+
+	inputs     []textinput.Model
+	focusIndex int
+	spinner    spinner.Model
+
+	original  *openOrdersItem
+	state     orderEntryState
+	err       error
+	order     orderRequest
+	signature string
+}
+
+// replaceOrderMsg selects the open order to amend
+type replaceOrderMsg struct {
+	order openOrdersItem
+}
+
+func newReplaceOrderModel(appStore *store.App) StageModel {
+	m := &replaceOrderModel{
+		appStore: appStore,
+		inputs:   make([]textinput.Model, roInputCount),
+		spinner:  spinner.New(spinner.WithSpinner(spinner.Points)),
+	}
+
+	for i := range m.inputs {
+		t := textinput.New()
+		switch i {
+		case roPrice:
+			t.Placeholder = "Price"
+		case roSize:
+			t.Placeholder = "Size"
+		case roPayer:
+			t.Placeholder = "Payer (token account to pay from; default public key)"
+		}
+		m.inputs[i] = t
+	}
+	return m
+}
+
+func (m *replaceOrderModel) Init(dispatch StageDispatcher) tea.Cmd {
+	m.dispatch = dispatch
+	m.original = nil
+	m.state = oeInput
+	m.focusIndex = 0
+	m.err = nil
+	return tea.Batch(focusInputs(m.inputs, m.focusIndex), textinput.Blink)
+}
+
+func (m *replaceOrderModel) Update(msg tea.Msg) (Stage, StageModel, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case replaceOrderMsg:
+		m.prefill(msg.order)
+		return StageReplaceOrder, m, focusInputs(m.inputs, m.focusIndex)
+	case orderSubmitMsg:
+		if m.state != oeSubmitting {
+			return StageReplaceOrder, m, nil
+		}
+		if msg.err != nil {
+			m.err = msg.err
+			m.state = oePreview
+			return StageReplaceOrder, m, nil
+		}
+		m.signature = msg.signature
+		m.state = oeDone
+		return StageReplaceOrder, m, nil
+	case spinner.TickMsg:
+		if m.state == oeSubmitting {
+			m.spinner, cmd = m.spinner.Update(msg)
+		}
+		return StageReplaceOrder, m, cmd
+	}
+
+	if m.original == nil {
+		return StageReplaceOrder, m, nil
+	}
+
+	switch m.state {
+	case oeInput:
+		cmd, submitted := updateForm(msg, m.inputs, &m.focusIndex)
+		if submitted {
+			m.preview()
+			return StageReplaceOrder, m, nil
+		}
+		return StageReplaceOrder, m, cmd
+	case oePreview:
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch {
+			case key.Matches(msg, keys.Submit):
+				return StageReplaceOrder, m, m.submit()
+			case key.Matches(msg, keys.Edit):
+				m.state = oeInput
+				m.err = nil
+				return StageReplaceOrder, m, focusInputs(m.inputs, m.focusIndex)
+			}
+		}
+	}
+	return StageReplaceOrder, m, nil
+}
+
+// prefill resets the form to the order's current price and remaining size
+func (m *replaceOrderModel) prefill(order openOrdersItem) {
+	m.original = &order
+	m.state = oeInput
+	m.focusIndex = 0
+	m.err = nil
+	m.inputs[roPrice].SetValue(formatFloat(order.price))
+	m.inputs[roSize].SetValue(formatFloat(order.remainingSize))
+	m.inputs[roPayer].SetValue("")
+}
+
+func (m *replaceOrderModel) preview() {
+	price, err := parseAmount("price", m.inputs[roPrice].Value())
+	if err != nil {
+		m.err = err
+		return
+	}
+	size, err := parseAmount("size", m.inputs[roSize].Value())
+	if err != nil {
+		m.err = err
+		return
+	}
+
+	// orders placed without a client order ID report it as empty or 0
+	clientOrderID, err := parseClientOrderID(m.original.clientOrderID)
+	if err != nil {
+		m.err = err
+		return
+	}
+
+	payer := strings.TrimSpace(m.inputs[roPayer].Value())
+	if payer == "" {
+		payer = m.appStore.CurrentSettings().PublicKey.String()
+	}
+
+	m.err = nil
+	m.order = orderRequest{
+		market:        m.original.market,
+		side:          m.original.side,
+		orderType:     orderTypeName(m.original.types),
+		types:         m.original.types,
+		price:         price,
+		size:          size,
+		clientOrderID: clientOrderID,
+		payer:         payer,
+	}
+	m.state = oePreview
+}
+
+func (m *replaceOrderModel) submit() tea.Cmd {
+	settings := m.appStore.CurrentSettings()
+	if settings.PublicKey.IsZero() || len(settings.PrivateKey) == 0 {
+		m.err = errors.New("a private and public key are required to trade: set them from the settings stage")
+		return nil
+	}
+	traderProvider, err := m.appStore.Connected()
+	if err != nil {
+		m.err = err
+		return nil
+	}
+
+	opts := provider.PostOrderOpts{ClientOrderID: m.order.clientOrderID}
+	if !settings.OpenOrdersAddress.IsZero() {
+		opts.OpenOrdersAddress = settings.OpenOrdersAddress.String()
+	}
+
+	m.err = nil
+	m.state = oeSubmitting
+	orderID := m.original.orderID
+	order := m.order
+	owner := settings.PublicKey.String()
+	dispatch := m.dispatch
+	go func() {
+		ctx, cancel := submitContext()
+		defer cancel()
+		signature, err := traderProvider.SubmitReplaceOrder(ctx, orderID, owner, order.payer, order.market, order.side, order.types, order.size, order.price, settings.Project, opts)
+		dispatch(orderSubmitMsg{signature: signature, err: submitErr(ctx, err)})
+	}()
+	return m.spinner.Tick
+}
+
+// CapturesKey also holds back while submitting, as leaving would drop the result and signature
+func (m *replaceOrderModel) CapturesKey(msg tea.KeyMsg) bool {
+	if m.state == oeSubmitting {
+		return key.Matches(msg, keys.Back)
+	}
+	return m.original != nil && m.state == oeInput && m.focusIndex < len(m.inputs) && capturesTextKey(msg)
+}
+
+func (m *replaceOrderModel) Busy() bool {
+	return m.state == oeSubmitting
+}
+
+func (m *replaceOrderModel) Bindings() []key.Binding {
+	switch {
+	case m.original == nil || m.state == oeDone:
+		return nil
+	case m.state == oePreview:
+		return []key.Binding{keys.Submit, keys.Edit}
+	default:
+		return []key.Binding{keys.NextField, keys.PrevField, keys.Submit}
+	}
+}
+
+func (m *replaceOrderModel) View() string {
+	var b strings.Builder
+
+	if m.original == nil {
+		b.WriteString(helpStyle.Render("(select an order to amend from the Open Orders list)"))
+		b.WriteRune('\n')
+		return b.String()
+	}
+
+	switch m.state {
+	case oeInput:
+		b.WriteString(fmt.Sprintf("Amend %v order %v in %v (%v @ %v, client order ID %v)\n\n",
+			m.original.side, m.original.orderID, m.original.market, formatFloat(m.original.remainingSize), formatFloat(m.original.price), m.original.clientOrderID))
+		b.WriteString(inputsView(m.inputs, m.focusIndex))
+	case oePreview, oeSubmitting:
+		b.WriteString(fmt.Sprintf("Replace order %v with:\n\n", m.original.orderID))
+		b.WriteString(orderPreviewView(m.order))
+		b.WriteString("\n\n")
+		if m.state == oeSubmitting {
+			b.WriteString(m.spinner.View())
+			b.WriteString(" submitting… (back is disabled until the result arrives)\n")
+		} else {
+			b.WriteString(helpStyle.Render(fmt.Sprintf("(%v to sign and submit • %v to edit)", keys.Submit.Help().Key, keys.Edit.Help().Key)))
+			b.WriteRune('\n')
+		}
+	case oeDone:
+		b.WriteString(fmt.Sprintf("Replaced order %v with:\n\n", m.original.orderID))
+		b.WriteString(orderPreviewView(m.order))
+		b.WriteString("\n\n")
+		b.WriteString(statusStyle.Render(fmt.Sprintf("submitted: %v", m.signature)))
+		b.WriteRune('\n')
+	}
+
+	if m.err != nil {
+		b.WriteString(errorStyle.Render(m.err.Error()))
+		b.WriteRune('\n')
+	}
+	return b.String()
+}
+
+// orderTypeName is the order entry name of an order's types
+func orderTypeName(types []pb.OrderType) string {
+	for _, t := range types {
+		switch t {
+		case pb.OrderType_OT_IOC:
+			return "ioc"
+		case pb.OrderType_OT_POST:
+			return "post"
+		}
+	}
+	return "limit"
+}
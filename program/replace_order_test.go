@@ -0,0 +1,45 @@
+package program
+
+import (
+	"github.com/aspin/solana-trader-tui/store"
+	"testing"
+)
+
+func newReplaceOrderDriver(t *testing.T, fake *store.FakeProvider) (*stageDriver, *replaceOrderModel) {
+	d := newStageDriver(t, newReplaceOrderModel(newTestApp(t, fake)))
+	d.update(replaceOrderMsg{order: newOpenOrdersItem(fake.OpenOrders["SOL/USDC"][0], "SOL/USDC")})
+	return d, d.model.(*replaceOrderModel)
+}
+
+func TestReplaceOrderSubmit(t *testing.T) {
+	fake := newFakeWithOrders("SOL/USDC", "1")
+	d, m := newReplaceOrderDriver(t, fake)
+	if v := m.inputs[roPrice].Value(); v != "10" {
+		t.Fatalf("price = %q, want the order's price", v)
+	}
+
+	m.inputs[roPrice].SetValue("12")
+	d.keys("tab", "tab", "tab", "enter")
+	if m.state != oePreview {
+		t.Fatalf("state = %v, want preview; err = %v", m.state, m.err)
+	}
+
+	d.keys("enter")
+	d.until("the replacement", func() bool {
+		return m.state == oeDone
+	})
+	if order := fake.OpenOrders["SOL/USDC"][0]; order.Price != 12 || order.RemainingSize != 1 {
+		t.Errorf("order = %v, want the price amended", order)
+	}
+}
+
+func TestReplaceOrderInvalid(t *testing.T) {
+	fake := newFakeWithOrders("SOL/USDC", "1")
+	d, m := newReplaceOrderDriver(t, fake)
+
+	m.inputs[roSize].SetValue("NaN")
+	d.keys("tab", "tab", "tab", "enter")
+	if m.state != oeInput || m.err == nil {
+		t.Errorf("state = %v, err = %v; want the form with an error", m.state, m.err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/aspin/solana-trader-tui/store"
 	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -42,7 +43,7 @@ type statusDoneMsg struct{}
 
 func newSettingsModel(appStore *store.App) StageModel {
 	m := settingsModel{
-		inputs:   make([]textinput.Model, 5),
+		inputs:   make([]textinput.Model, 10),
 		appStore: appStore,
 	}
 
@@ -51,33 +52,30 @@ func newSettingsModel(appStore *store.App) StageModel {
 		switch i {
 		case 0:
 			t.Placeholder = "bloXroute Auth Header"
-			t.SetValue(appStore.Settings.AuthHeader)
 		case 1:
 			t.Placeholder = "Private Key"
-			t.SetValue(appStore.Settings.PrivateKey.String())
 			t.EchoMode = textinput.EchoPassword
 			t.EchoCharacter = '*'
 		case 2:
 			t.Placeholder = "Public Key"
-
-			publicKey := appStore.Settings.PublicKey
-			if !publicKey.IsZero() {
-				t.SetValue(publicKey.String())
-			}
 		case 3:
 			t.Placeholder = "Open Orders Address"
-
-			openOrdersAddress := appStore.Settings.OpenOrdersAddress
-			if !openOrdersAddress.IsZero() {
-				t.SetValue(openOrdersAddress.String())
-			}
 		case 4:
 			t.Placeholder = "Project"
-
-			project := appStore.Settings.Project
-			if project != pb.Project_P_UNKNOWN {
-				t.SetValue(project.String())
-			}
+		case 5:
+			t.Placeholder = "Network (mainnet, testnet, devnet, custom)"
+		case 6:
+			t.Placeholder = "Custom Endpoint (host:port)"
+		case 7:
+			t.Placeholder = "Custom Endpoint TLS (on, off)"
+		case 8:
+			t.Placeholder = "Passphrase (leave empty to store the private key unencrypted)"
+			t.EchoMode = textinput.EchoPassword
+			t.EchoCharacter = '*'
+		case 9:
+			t.Placeholder = "Confirm Passphrase"
+			t.EchoMode = textinput.EchoPassword
+			t.EchoCharacter = '*'
 		}
 
 		m.inputs[i] = t
@@ -87,8 +85,49 @@ func newSettingsModel(appStore *store.App) StageModel {
 	return &m
 }
 
+// loadValues fills the inputs from the current settings, which may have changed since the last visit (e.g. after unlocking)
+func (m *settingsModel) loadValues() {
+	settings := m.appStore.CurrentSettings()
+
+	m.inputs[0].SetValue(settings.AuthHeader)
+	m.inputs[1].SetValue("")
+	if len(settings.PrivateKey) > 0 {
+		m.inputs[1].SetValue(settings.PrivateKey.String())
+	}
+
+	m.inputs[2].SetValue("")
+	if !settings.PublicKey.IsZero() {
+		m.inputs[2].SetValue(settings.PublicKey.String())
+	}
+
+	m.inputs[3].SetValue("")
+	if !settings.OpenOrdersAddress.IsZero() {
+		m.inputs[3].SetValue(settings.OpenOrdersAddress.String())
+	}
+
+	m.inputs[4].SetValue("")
+	if settings.Project != pb.Project_P_UNKNOWN {
+		m.inputs[4].SetValue(settings.Project.String())
+	}
+
+	m.inputs[5].SetValue(string(settings.Network))
+	m.inputs[6].SetValue(settings.Endpoint)
+	m.inputs[7].SetValue("")
+	if settings.Network == store.NetworkCustom {
+		m.inputs[7].SetValue(tlsString(settings.UseTLS))
+	}
+
+	m.inputs[8].SetValue(settings.Passphrase)
+	m.inputs[9].SetValue(settings.Passphrase)
+}
+
 func (m *settingsModel) Init(dispatch StageDispatcher) tea.Cmd {
+	m.loadValues()
 	m.focusIndex = 0
+	for i := range m.inputs {
+		m.inputs[i].Blur()
+		m.inputs[i].PromptStyle = noStyle
+	}
 	m.inputs[0].Focus()
 	m.inputs[0].PromptStyle = focusedStyle
 	m.dispatch = dispatch
@@ -100,9 +139,8 @@ func (m *settingsModel) Update(msg tea.Msg) (Stage, StageModel, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch k := msg.Type; k {
-		case tea.KeyTab, tea.KeyShiftTab, tea.KeyEnter, tea.KeyUp, tea.KeyDown:
-			if k == tea.KeyEnter && m.focusIndex == len(m.inputs) {
+		if key.Matches(msg, keys.NextField, keys.PrevField, keys.Submit) {
+			if key.Matches(msg, keys.Submit) && m.focusIndex == len(m.inputs) {
 				err := m.submit()
 				if err != nil {
 					m.err = err
@@ -110,7 +148,7 @@ func (m *settingsModel) Update(msg tea.Msg) (Stage, StageModel, tea.Cmd) {
 				}
 			}
 
-			if k == tea.KeyUp || k == tea.KeyShiftTab {
+			if key.Matches(msg, keys.PrevField) {
 				m.focusIndex--
 			} else {
 				m.focusIndex++
@@ -147,6 +185,14 @@ func (m *settingsModel) Update(msg tea.Msg) (Stage, StageModel, tea.Cmd) {
 	return StageSettings, m, cmd
 }
 
+func (m *settingsModel) CapturesKey(msg tea.KeyMsg) bool {
+	return capturesDisconnectedBack(m.appStore, msg) || m.focusIndex < len(m.inputs) && capturesTextKey(msg)
+}
+
+func (m *settingsModel) Bindings() []key.Binding {
+	return []key.Binding{keys.NextField, keys.PrevField, keys.Submit}
+}
+
 func (m *settingsModel) updateInputs(msg tea.Msg) tea.Cmd {
 	cmds := make([]tea.Cmd, len(m.inputs))
 
@@ -157,37 +203,35 @@ func (m *settingsModel) updateInputs(msg tea.Msg) tea.Cmd {
 	return tea.Batch(cmds...)
 }
 
-func (m settingsModel) submit() error {
-	authHeader := m.inputs[0].Value()
-	if authHeader == "" {
+// submit validates every field into a copy of the settings, only applying and saving them once all are valid
+func (m *settingsModel) submit() error {
+	settings := m.appStore.CurrentSettings()
+
+	settings.AuthHeader = m.inputs[0].Value()
+	if settings.AuthHeader == "" {
 		return errors.New("auth header cannot be empty")
 	}
-	m.appStore.Settings.AuthHeader = authHeader
 
-	privateKeyStr := m.inputs[1].Value()
-	if privateKeyStr != "" {
+	settings.PrivateKey = nil
+	if privateKeyStr := m.inputs[1].Value(); privateKeyStr != "" {
 		privateKey, err := solana.PrivateKeyFromBase58(privateKeyStr)
 		if err != nil {
 			return fmt.Errorf("invalid private key: %w", err)
 		}
-		m.appStore.Settings.PrivateKey = privateKey
-	} else {
-		m.appStore.Settings.PrivateKey = nil
+		settings.PrivateKey = privateKey
 	}
 
-	publicKeyStr := m.inputs[2].Value()
-	publicKey, err := solana.PublicKeyFromBase58(publicKeyStr)
+	publicKey, err := solana.PublicKeyFromBase58(m.inputs[2].Value())
 	if err != nil {
 		return fmt.Errorf("invalid public key: %w", err)
 	}
-	m.appStore.Settings.PublicKey = publicKey
+	settings.PublicKey = publicKey
 
-	openOrdersAddressStr := m.inputs[3].Value()
-	openOrdersAddress, err := solana.PublicKeyFromBase58(openOrdersAddressStr)
+	openOrdersAddress, err := solana.PublicKeyFromBase58(m.inputs[3].Value())
 	if err != nil {
 		return fmt.Errorf("invalid open orders address key: %w", err)
 	}
-	m.appStore.Settings.OpenOrdersAddress = openOrdersAddress
+	settings.OpenOrdersAddress = openOrdersAddress
 
 	projectStr := m.inputs[4].Value()
 	projectInt, ok := pb.Project_value[projectStr]
@@ -195,22 +239,74 @@ func (m settingsModel) submit() error {
 	if !ok || project == pb.Project_P_UNKNOWN {
 		return fmt.Errorf("invalid project value: %v", projectStr)
 	}
-	m.appStore.Settings.Project = project
+	settings.Project = project
 
+	network, err := store.NetworkFromString(m.inputs[5].Value())
+	if err != nil {
+		return err
+	}
+
+	endpoint := m.inputs[6].Value()
+	useTLS := true
+	if network == store.NetworkCustom {
+		if err = store.ValidateEndpoint(endpoint); err != nil {
+			return err
+		}
+
+		useTLS, err = parseTLS(m.inputs[7].Value())
+		if err != nil {
+			return err
+		}
+	}
+	settings.Network = network
+	settings.Endpoint = endpoint
+	settings.UseTLS = useTLS
+
+	passphrase := m.inputs[8].Value()
+	if passphrase != m.inputs[9].Value() {
+		return errors.New("passphrases do not match")
+	}
+	settings.Passphrase = passphrase
+
+	m.appStore.UpdateSettings(settings)
+	if err = m.appStore.Save(); err != nil {
+		return fmt.Errorf("could not save settings: %w", err)
+	}
+
+	m.err = nil
+	dispatch := m.dispatch
 	go func() {
-		m.dispatch(statusMsg{status: "connecting..."})
-		err = m.appStore.Connect()
+		dispatch(statusMsg{status: "connecting..."})
+		err := m.appStore.Reconnect()
 		if err != nil {
-			m.dispatch(statusErrMsg{err: err})
+			dispatch(statusErrMsg{err: err})
 			return
 		}
-		m.dispatch(statusMsg{status: "connected!"})
+		dispatch(statusMsg{status: "connected!"})
 		time.Sleep(500 * time.Millisecond)
-		m.dispatch(statusDoneMsg{})
+		dispatch(statusDoneMsg{})
 	}()
 	return nil
 }
 
+func tlsString(useTLS bool) string {
+	if useTLS {
+		return "on"
+	}
+	return "off"
+}
+
+func parseTLS(s string) (bool, error) {
+	switch s {
+	case "", "on":
+		return true, nil
+	case "off":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid TLS value: %v", s)
+	}
+}
+
 func (m settingsModel) View() string {
 	var b strings.Builder
 
@@ -235,5 +331,7 @@ func (m settingsModel) View() string {
 		b.WriteString(errorStyle.Render(m.err.Error()))
 		b.WriteRune('\n')
 	}
+
+	b.WriteString(disconnectedView(m.appStore))
 	return b.String()
 }
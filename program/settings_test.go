@@ -0,0 +1,78 @@
+package program
+
+import (
+	"github.com/aspin/solana-trader-tui/store"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"github.com/gagliardetto/solana-go"
+	"testing"
+)
+
+// fillSettings enters valid settings into every input and focuses the submit button
+func fillSettings(m *settingsModel, network, endpoint, tls, passphrase, confirmation string) {
+	wallet := solana.NewWallet()
+	values := []string{"auth", wallet.PrivateKey.String(), wallet.PublicKey().String(), solana.NewWallet().PublicKey().String(),
+		pb.Project_P_OPENBOOK.String(), network, endpoint, tls, passphrase, confirmation}
+	for i, v := range values {
+		m.inputs[i].SetValue(v)
+	}
+	m.focusIndex = len(m.inputs)
+}
+
+func newSettingsDriver(t *testing.T) (*stageDriver, *settingsModel, *store.App) {
+	appStore := newTestApp(t, store.NewFakeProvider())
+	appStore.Settings.Transport = store.TransportFake
+	appStore.Settings.AuthHeader = "previous"
+	d := newStageDriver(t, newSettingsModel(appStore))
+	return d, d.model.(*settingsModel), appStore
+}
+
+func TestSettingsSubmit(t *testing.T) {
+	d, m, appStore := newSettingsDriver(t)
+
+	fillSettings(m, "custom", "localhost:1809", "off", "secret", "typo")
+	d.keys("enter")
+	if m.err == nil {
+		t.Fatal("mismatched passphrases accepted")
+	}
+
+	m.inputs[9].SetValue("secret")
+	d.keys("enter")
+	if m.err != nil {
+		t.Fatalf("err = %v after fixing the passphrase, want it cleared", m.err)
+	}
+	d.until("the reconnection", func() bool {
+		return m.status == "connected!"
+	})
+
+	settings := appStore.CurrentSettings()
+	if settings.AuthHeader != "auth" || settings.Network != store.NetworkCustom || settings.Endpoint != "localhost:1809" || settings.UseTLS || settings.Passphrase != "secret" {
+		t.Errorf("settings = %+v, want the submitted ones", settings)
+	}
+	if _, err := appStore.Connected(); err != nil {
+		t.Errorf("Connected() = %v after submitting", err)
+	}
+}
+
+func TestSettingsInvalidLeavesSettings(t *testing.T) {
+	tests := []struct {
+		name                            string
+		network, endpoint, tls, confirm string
+	}{
+		{name: "network", network: "moonnet"},
+		{name: "endpoint", network: "custom", endpoint: "localhost"},
+		{name: "tls", network: "custom", endpoint: "localhost:1809", tls: "maybe"},
+		{name: "passphrase", network: "mainnet", confirm: "typo"},
+	}
+	for _, test := range tests {
+		d, m, appStore := newSettingsDriver(t)
+		fillSettings(m, test.network, test.endpoint, test.tls, "", test.confirm)
+		d.keys("enter")
+
+		if m.err == nil {
+			t.Errorf("%v: invalid settings accepted", test.name)
+		}
+		if settings := appStore.CurrentSettings(); settings.AuthHeader != "previous" || settings.Network != "" {
+			t.Errorf("%v: settings = %+v, want them unchanged", test.name, settings)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package program
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aspin/solana-trader-tui/component/listquery"
+	"github.com/aspin/solana-trader-tui/store"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gagliardetto/solana-go"
+	"strings"
+)
+
+type settleModel struct {
+	appStore *store.App
+	dispatch StageDispatcher
+
+	listquery listquery.Model
+
+	marks marks[unsettledItem]
+	err   error
+}
+
+func newSettleModel(appStore *store.App) StageModel {
+	marketsInput := textinput.New()
+	marketsInput.Placeholder = "Market Names or Public Keys, comma separated (e.g. SOL/USDC, SOL/USDT)"
+	marketsInput.Focus()
+	marketsInput.PromptStyle = focusedStyle
+
+	lq := listquery.New([]textinput.Model{marketsInput}, spinner.Points, list.NewDefaultDelegate(), nil)
+	lq.KeyMap = listQueryKeyMap()
+	lq.Timeout = queryTimeout(appStore)
+	lq.PollInterval = pollInterval(appStore)
+	lq.SetColumns(unsettledColumns...)
+
+	m := &settleModel{
+		appStore:  appStore,
+		listquery: lq,
+	}
+	m.marks.reset()
+	m.listquery.SetQuery(m.fetchUnsettled)
+	m.listquery.SetHistory(appStore.History["settle"], recordHistory(appStore, "settle"))
+	return m
+}
+
+func (m *settleModel) Init(dispatch StageDispatcher) tea.Cmd {
+	m.dispatch = dispatch
+	m.marks.reset()
+	m.err = nil
+	return m.listquery.Init(m.appStore.UI.WindowWidth, m.appStore.UI.WindowHeight)
+}
+
+func (m *settleModel) Update(msg tea.Msg) (Stage, StageModel, tea.Cmd) {
+	var (
+		cmd  tea.Cmd
+		exit bool
+	)
+
+	switch msg := msg.(type) {
+	case markStatusMsg:
+		return StageSettle, m, m.marks.status(&m.listquery, msg)
+	case markDoneMsg:
+		if !m.marks.done(msg, "settlement(s)") {
+			return StageSettle, m, nil
+		}
+		return StageSettle, m, m.listquery.Refresh()
+	case marketPickedMsg:
+		markets := strings.TrimSpace(m.listquery.InputValue(0))
+		if markets != "" {
+			markets += ", "
+		}
+		m.listquery.SetInputValue(0, markets+msg.market)
+		return StageSettle, m, nil
+	case listquery.ResultMsg:
+		// settle results stay visible across the refresh, and marks are kept across polls
+		m.marks.restore(&m.listquery, msg.Items)
+	case tea.KeyMsg:
+		if m.listquery.Focused() == 0 && key.Matches(msg, keys.PickMarket) {
+			stage, cmd := pickMarket()
+			return stage, m, cmd
+		}
+		if m.marks.confirming != nil {
+			if items, ok := m.marks.confirm(msg); ok {
+				return StageSettle, m, m.startSettle(items)
+			}
+			return StageSettle, m, nil
+		}
+
+		if m.listquery.Showing() && !m.marks.running {
+			switch {
+			case key.Matches(msg, keys.Mark):
+				return StageSettle, m, m.marks.toggle(&m.listquery)
+			case key.Matches(msg, keys.Settle):
+				m.confirmSettle()
+				return StageSettle, m, nil
+			}
+		}
+	}
+
+	m.listquery, cmd, exit = m.listquery.Update(msg)
+	if exit {
+		return StageBack, m, nil
+	}
+	return StageSettle, m, cmd
+}
+
+// confirmSettle asks for confirmation to settle the marked markets, or the highlighted one if none are marked
+func (m *settleModel) confirmSettle() {
+	items := m.marks.selection(&m.listquery)
+	if len(items) == 0 {
+		m.err = errors.New("no unsettled funds to settle")
+		return
+	}
+
+	m.err = nil
+	m.marks.confirming = items
+}
+
+func (m *settleModel) startSettle(items []unsettledItem) tea.Cmd {
+	settings := m.appStore.CurrentSettings()
+	owner := settings.PublicKey
+	if owner.IsZero() || len(settings.PrivateKey) == 0 {
+		m.err = errors.New("a private and public key are required to settle funds: set them from the settings stage")
+		return nil
+	}
+	traderProvider, err := m.appStore.Connected()
+	if err != nil {
+		m.err = err
+		return nil
+	}
+
+	m.err = nil
+	cmd := m.marks.start(&m.listquery, items, "settling…")
+
+	project := settings.Project
+	dispatch := m.dispatch
+	m.marks.run(func() {
+		runMarked(dispatch, items, "settle", "settled", func(item unsettledItem) (string, error) {
+			return settle(traderProvider, owner, item, project)
+		})
+	})
+	return cmd
+}
+
+func settle(traderProvider store.TraderProvider, owner solana.PublicKey, item unsettledItem, project pb.Project) (string, error) {
+	baseWallet, err := tokenWallet(owner, item.baseMint)
+	if err != nil {
+		return "", err
+	}
+	quoteWallet, err := tokenWallet(owner, item.quoteMint)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := submitContext()
+	defer cancel()
+	signature, err := traderProvider.SubmitSettle(ctx, owner.String(), item.market, baseWallet, quoteWallet, item.account, project, false)
+	return signature, submitErr(ctx, err)
+}
+
+// tokenWallet is the owner's account receiving settled funds of mint: the owner itself for SOL, otherwise its associated token account
+func tokenWallet(owner solana.PublicKey, mint string) (string, error) {
+	mintKey, err := solana.PublicKeyFromBase58(mint)
+	if err != nil {
+		return "", fmt.Errorf("invalid token mint %q: %w", mint, err)
+	}
+	if mintKey.Equals(solana.SolMint) {
+		return owner.String(), nil
+	}
+
+	wallet, _, err := solana.FindAssociatedTokenAddress(owner, mintKey)
+	if err != nil {
+		return "", err
+	}
+	return wallet.String(), nil
+}
+
+func (m *settleModel) CapturesKey(msg tea.KeyMsg) bool {
+	return m.marks.confirming != nil || m.listquery.Filtering() || m.listquery.Cancels(msg) || (m.listquery.Typing() && capturesTextKey(msg))
+}
+
+func (m *settleModel) Bindings() []key.Binding {
+	bindings := m.listquery.Bindings()
+	if m.listquery.Showing() {
+		bindings = append(bindings, keys.Mark, keys.Settle)
+	}
+	if m.listquery.Focused() == 0 {
+		bindings = append(bindings, keys.PickMarket)
+	}
+	return bindings
+}
+
+func (m *settleModel) Leave() {
+	m.listquery.Cancel()
+}
+
+func (m *settleModel) Busy() bool {
+	return m.listquery.Loading() || m.marks.busy()
+}
+
+// fetchUnsettled lists the unsettled funds of each market, limited to the configured open orders address if there is one
+func (m *settleModel) fetchUnsettled(ctx context.Context, vs []string) ([]list.Item, error) {
+	settings := m.appStore.CurrentSettings()
+	if settings.PublicKey.IsZero() {
+		return nil, errors.New("a public key is required to list unsettled funds: set it from the settings stage")
+	}
+	traderProvider, err := m.appStore.Connected()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]list.Item, 0)
+	for _, market := range strings.Split(vs[0], ",") {
+		market = strings.TrimSpace(market)
+		if market == "" {
+			continue
+		}
+
+		unsettled, err := traderProvider.GetUnsettled(ctx, market, settings.PublicKey.String(), settings.Project)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", market, err)
+		}
+		for _, account := range unsettled.Unsettled {
+			if !settings.OpenOrdersAddress.IsZero() && account.Account != settings.OpenOrdersAddress.String() {
+				continue
+			}
+			items = append(items, newUnsettledItem(market, account))
+		}
+	}
+	return items, nil
+}
+
+func (m settleModel) View() string {
+	var b strings.Builder
+	b.WriteString(m.listquery.View())
+
+	b.WriteRune('\n')
+	switch {
+	case m.marks.confirming != nil:
+		markets := make([]string, 0, len(m.marks.confirming))
+		for _, item := range m.marks.confirming {
+			markets = append(markets, item.market)
+		}
+		b.WriteString(confirmStyle.Render(fmt.Sprintf("Settle funds in %v? (%v/n)", strings.Join(markets, ", "), keys.Confirm.Help().Key)))
+	case m.err != nil:
+		b.WriteString(errorStyle.Render(m.err.Error()))
+	case m.marks.running:
+		b.WriteString(statusStyle.Render("Submitting settlements…"))
+	case m.marks.summary != "":
+		b.WriteString(statusStyle.Render(m.marks.summary))
+	}
+	return b.String()
+}
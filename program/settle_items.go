@@ -0,0 +1,72 @@
+package program
+
+import (
+	"github.com/aspin/solana-trader-tui/component/listquery"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+)
+
+var unsettledColumns = []listquery.Column{
+	{Title: ""},
+	{Title: "MARKET"},
+	{Title: "ACCOUNT"},
+	{Title: "BASE", Numeric: true},
+	{Title: "QUOTE", Numeric: true},
+	{Title: "STATUS"},
+}
+
+type unsettledItem struct {
+	market      string
+	account     string
+	baseMint    string
+	baseAmount  float64
+	quoteMint   string
+	quoteAmount float64
+
+	// marked markets are settled together; status reports the last settle attempt
+	marked bool
+	status string
+}
+
+func (i unsettledItem) FilterValue() string {
+	return i.market
+}
+
+func (i unsettledItem) Cells() []string {
+	mark := ""
+	if i.marked {
+		mark = "●"
+	}
+	return []string{mark, i.market, i.account, formatFloat(i.baseAmount), formatFloat(i.quoteAmount), i.status}
+}
+
+// Key identifies the item across refreshes
+func (i unsettledItem) Key() string {
+	return i.market + "/" + i.account
+}
+
+func (i unsettledItem) isMarked() bool {
+	return i.marked
+}
+
+func (i unsettledItem) withMark(marked bool) unsettledItem {
+	i.marked = marked
+	return i
+}
+
+func (i unsettledItem) withStatus(status string) unsettledItem {
+	i.status = status
+	return i
+}
+
+func newUnsettledItem(market string, account *pb.UnsettledAccount) unsettledItem {
+	item := unsettledItem{market: market, account: account.Account}
+	if account.BaseToken != nil {
+		item.baseMint = account.BaseToken.Address
+		item.baseAmount = account.BaseToken.Amount
+	}
+	if account.QuoteToken != nil {
+		item.quoteMint = account.QuoteToken.Address
+		item.quoteAmount = account.QuoteToken.Amount
+	}
+	return item
+}
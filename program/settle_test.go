@@ -0,0 +1,106 @@
+package program
+
+import (
+	"context"
+	"github.com/aspin/solana-trader-tui/store"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"github.com/gagliardetto/solana-go"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newFakeWithUnsettled(markets ...string) *store.FakeProvider {
+	fake := store.NewFakeProvider()
+	quoteMint := solana.NewWallet().PublicKey().String()
+	for _, market := range markets {
+		fake.Unsettled[market] = []*pb.UnsettledAccount{{
+			Account:    solana.NewWallet().PublicKey().String(),
+			BaseToken:  &pb.UnsettledAccountToken{Address: solana.SolMint.String(), Amount: 1},
+			QuoteToken: &pb.UnsettledAccountToken{Address: quoteMint, Amount: 10},
+		}}
+	}
+	return fake
+}
+
+func TestSettleMarked(t *testing.T) {
+	fake := newFakeWithUnsettled("SOL/USDC", "SOL/USDT")
+	d := newStageDriver(t, newSettleModel(newTestApp(t, fake)))
+	m := d.model.(*settleModel)
+
+	d.keys("SOL/USDC, SOL/USDT", "enter", "enter")
+	d.until("the unsettled funds", func() bool {
+		return len(m.listquery.Items()) == 2
+	})
+
+	d.keys(" ", "j", " ", "s")
+	if len(m.marks.confirming) != 2 {
+		t.Fatalf("confirming %v market(s), want both marked", len(m.marks.confirming))
+	}
+
+	d.keys("y")
+	d.until("the settlements", func() bool {
+		return !m.marks.running && m.marks.summary != "" && !m.listquery.Loading()
+	})
+	if m.marks.summary != "2 of 2 settlement(s) submitted" {
+		t.Errorf("summary = %q", m.marks.summary)
+	}
+	for _, item := range m.marks.items(&m.listquery) {
+		if item.marked || item.baseAmount != 0 || item.quoteAmount != 0 {
+			t.Errorf("%v: marked = %v, base = %v, quote = %v after settling", item.market, item.marked, item.baseAmount, item.quoteAmount)
+		}
+	}
+}
+
+func TestSettleNothingSelected(t *testing.T) {
+	d := newStageDriver(t, newSettleModel(newTestApp(t, store.NewFakeProvider())))
+	m := d.model.(*settleModel)
+
+	d.keys("SOL/USDC", "enter", "enter")
+	d.until("the unsettled funds", func() bool {
+		return m.listquery.Showing() && !m.listquery.Loading()
+	})
+
+	d.keys("s")
+	if m.marks.confirming != nil || m.err == nil {
+		t.Errorf("confirming = %v, err = %v; want an error", m.marks.confirming, m.err)
+	}
+}
+
+// hangingSettles never responds to settlements, until the context finishes
+type hangingSettles struct {
+	*store.FakeProvider
+}
+
+func (p hangingSettles) SubmitSettle(ctx context.Context, owner, market, baseTokenWallet, quoteTokenWallet, openOrders string, project pb.Project, skipPreFlight bool) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestSettleTimeout(t *testing.T) {
+	defer func(timeout time.Duration) {
+		submitTimeout = timeout
+	}(submitTimeout)
+	submitTimeout = 50 * time.Millisecond
+
+	d := newStageDriver(t, newSettleModel(newTestApp(t, hangingSettles{newFakeWithUnsettled("SOL/USDC")})))
+	m := d.model.(*settleModel)
+
+	d.keys("SOL/USDC", "enter", "enter")
+	d.until("the unsettled funds", func() bool {
+		return len(m.listquery.Items()) == 1
+	})
+
+	d.keys("s", "y")
+	d.until("the settlement to time out", func() bool {
+		return !m.marks.running && m.marks.summary != ""
+	})
+	for _, status := range m.marks.statuses {
+		if !strings.Contains(status, "outcome is unknown") {
+			t.Errorf("status = %q, want the outcome reported unknown", status)
+		}
+	}
+	if m.marks.summary != "0 of 1 settlement(s) submitted" {
+		t.Errorf("summary = %q", m.marks.summary)
+	}
+}
@@ -1,12 +1,31 @@
 package program
 
-import tea "github.com/charmbracelet/bubbletea"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aspin/solana-trader-tui/component/listquery"
+	"github.com/aspin/solana-trader-tui/store"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"log"
+	"time"
+)
 
 type Stage int
 
-// StageDispatcher sends messages to the global context to be processed
+// StageDispatcher sends messages to the global context to be processed. Messages are only delivered while the stage
+// that dispatched them is current and has not been re-entered since, so late results of abandoned work are dropped.
+// Goroutines must capture the dispatcher when they start, as the stage's field is replaced on each Init.
 type StageDispatcher func(msg tea.Msg)
 
+// envelope scopes a dispatched message to the stage and request it was dispatched for
+type envelope struct {
+	stage   Stage
+	request int
+	msg     tea.Msg
+}
+
 type StageModel interface {
 	Init(dispatch StageDispatcher) tea.Cmd
 
@@ -15,11 +34,126 @@ type StageModel interface {
 	View() string
 }
 
+// KeyCapturer is implemented by stages that need global keys such as esc, backspace or q for themselves, e.g. while typing or filtering
+type KeyCapturer interface {
+	CapturesKey(msg tea.KeyMsg) bool
+}
+
+// HelpProvider is implemented by stages to list their currently active bindings in the help overlay
+type HelpProvider interface {
+	Bindings() []key.Binding
+}
+
+// BusyReporter is implemented by stages with work that would be lost by quitting, such as in-flight requests or open streams
+type BusyReporter interface {
+	Busy() bool
+}
+
+// Leaver is implemented by stages that hold resources, such as streams, that must be released when the stage is left
+type Leaver interface {
+	Leave()
+}
+
+// capturesTextKey reports whether a stage with a focused text input needs msg: everything except back, though backspace is always needed for editing
+func capturesTextKey(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyBackspace || !key.Matches(msg, keys.Back)
+}
+
+// capturesDisconnectedBack keeps the stages that connect a profile, such as unlock and settings, from being left before
+// it has connected, as every other stage needs the API client
+func capturesDisconnectedBack(appStore *store.App, msg tea.KeyMsg) bool {
+	_, err := appStore.Connected()
+	return err != nil && key.Matches(msg, keys.Back)
+}
+
+// disconnectedView explains why back does nothing while the active profile is not connected
+func disconnectedView(appStore *store.App) string {
+	if _, err := appStore.Connected(); err == nil {
+		return ""
+	}
+	return helpStyle.Render(fmt.Sprintf("(the profile must connect before going back • %v to quit)", keys.ForceQuit.Help().Key)) + "\n"
+}
+
+func listQueryKeyMap() listquery.KeyMap {
+	return listquery.KeyMap{
+		NextField: keys.NextField,
+		PrevField: keys.PrevField,
+		Submit:    keys.Submit,
+		Cancel:    keys.Cancel,
+		Poll:      keys.Pause,
+		Sort:      keys.Sort,
+
+		HistoryPrev: keys.HistoryPrev,
+		HistoryNext: keys.HistoryNext,
+	}
+}
+
+// recordHistory persists a stage's list query history in the state file
+func recordHistory(appStore *store.App, stage string) func([][]string) {
+	return func(history [][]string) {
+		appStore.SetHistory(stage, history)
+		if err := appStore.SaveState(); err != nil {
+			log.Printf("could not save query history: %v", err)
+		}
+	}
+}
+
+// submitTimeout bounds transaction submissions, so a hung call cannot leave a stage submitting forever
+var submitTimeout = 60 * time.Second
+
+func submitContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), submitTimeout)
+}
+
+// submitErr explains a submission that timed out, in place of the provider's error: its transaction may still land, so
+// the user must check before retrying
+func submitErr(ctx context.Context, err error) error {
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("submission timed out after %v; its outcome is unknown, check open orders before retrying: %w", submitTimeout, ctx.Err())
+	}
+	return err
+}
+
+// queryTimeout is the configured bound on list queries, or the component's default
+func queryTimeout(appStore *store.App) time.Duration {
+	if appStore.QueryTimeout > 0 {
+		return appStore.QueryTimeout
+	}
+	return listquery.DefaultTimeout
+}
+
+// pollInterval is the configured list refresh interval, the component's default, or zero if polling is disabled
+func pollInterval(appStore *store.App) time.Duration {
+	switch {
+	case appStore.PollInterval < 0:
+		return 0
+	case appStore.PollInterval > 0:
+		return appStore.PollInterval
+	default:
+		return listquery.DefaultPollInterval
+	}
+}
+
 var (
-	StageExit       Stage = 0
-	StageMenu       Stage = 1
-	StageSettings   Stage = 2
-	StageOpenOrders Stage = 3
-	StageView       Stage = 4
-	StageError      Stage = 5
+	// StageBack returns to the previous stage in the navigation history
+	StageBack          Stage = -1
+	StageExit          Stage = 0
+	StageMenu          Stage = 1
+	StageSettings      Stage = 2
+	StageOpenOrders    Stage = 3
+	StageView          Stage = 4
+	StageError         Stage = 5
+	StageUnlock        Stage = 6
+	StageProfiles      Stage = 7
+	StageOrderbook     Stage = 8
+	StageStream        Stage = 9
+	StageOrderEntry    Stage = 10
+	StageReplaceOrder  Stage = 11
+	StageSettle        Stage = 12
+	StageBalances      Stage = 13
+	StageMarkets       Stage = 14
+	StageTickers       Stage = 15
+	StageTimeSales     Stage = 16
+	StageSwap          Stage = 17
+	StageNotifications Stage = 18
 )
@@ -0,0 +1,109 @@
+package program
+
+import (
+	"github.com/aspin/solana-trader-tui/store"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gagliardetto/solana-go"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// stageDriver runs a stage the way appModel does: commands run concurrently and their messages, along with those
+// dispatched by the stage, are fed back through Update
+type stageDriver struct {
+	t     *testing.T
+	model StageModel
+	msgs  chan tea.Msg
+	done  chan struct{}
+}
+
+func newStageDriver(t *testing.T, model StageModel) *stageDriver {
+	d := &stageDriver{t: t, model: model, msgs: make(chan tea.Msg, 64), done: make(chan struct{})}
+	t.Cleanup(func() {
+		close(d.done)
+	})
+	d.run(model.Init(d.send))
+	return d
+}
+
+// newTestApp connects an app with a signing key to provider
+func newTestApp(t *testing.T, provider store.TraderProvider) *store.App {
+	wallet := solana.NewWallet()
+	return &store.App{
+		UI:             store.UI{WindowWidth: 120, WindowHeight: 40},
+		ConfigFile:     filepath.Join(t.TempDir(), "config.json"),
+		Provider:       provider,
+		PollInterval:   -1,
+		TickerInterval: time.Hour,
+		Settings: store.Settings{
+			PrivateKey: wallet.PrivateKey,
+			PublicKey:  wallet.PublicKey(),
+		},
+	}
+}
+
+func (d *stageDriver) send(msg tea.Msg) {
+	select {
+	case d.msgs <- msg:
+	case <-d.done:
+	}
+}
+
+func (d *stageDriver) run(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	go func() {
+		if msg := cmd(); msg != nil {
+			d.send(msg)
+		}
+	}()
+}
+
+// update passes msg to the stage
+func (d *stageDriver) update(msg tea.Msg) {
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for _, cmd := range batch {
+			d.run(cmd)
+		}
+		return
+	}
+
+	_, model, cmd := d.model.Update(msg)
+	d.model = model
+	d.run(cmd)
+}
+
+// keys types each key in turn, e.g. "enter" or "SOL/USDC" as runes
+func (d *stageDriver) keys(keys ...string) {
+	for _, k := range keys {
+		switch k {
+		case "enter":
+			d.update(tea.KeyMsg{Type: tea.KeyEnter})
+		case "tab":
+			d.update(tea.KeyMsg{Type: tea.KeyTab})
+		case "esc":
+			d.update(tea.KeyMsg{Type: tea.KeyEsc})
+		default:
+			d.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		}
+	}
+}
+
+// until processes messages until cond holds, failing the test if it does not within a few seconds. cond is also
+// checked periodically, for state changed by goroutines without a message.
+func (d *stageDriver) until(description string, cond func() bool) {
+	d.t.Helper()
+
+	deadline := time.After(5 * time.Second)
+	for !cond() {
+		select {
+		case msg := <-d.msgs:
+			d.update(msg)
+		case <-time.After(10 * time.Millisecond):
+		case <-deadline:
+			d.t.Fatalf("timed out waiting for %v; view:\n%v", description, d.model.View())
+		}
+	}
+}
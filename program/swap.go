@@ -0,0 +1,460 @@
+package program
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aspin/solana-trader-tui/store"
+	"github.com/bloXroute-Labs/solana-trader-client-go/provider"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"github.com/bloXroute-Labs/solana-trader-proto/common"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultSlippage = 0.5
+	routesPerQuote  = 3
+)
+
+// swapProjects are the AMM projects quoted for swaps
+var swapProjects = []pb.Project{pb.Project_P_JUPITER, pb.Project_P_RAYDIUM}
+
+type swapState int
+
+const (
+	swInput swapState = iota
+	swQuoting
+	swQuotes
+	swSubmitting
+	swDone
+)
+
+// swap input indexes
+const (
+	swInToken = iota
+	swOutToken
+	swAmount
+	swSlippage
+	swInputCount
+)
+
+type swapModel struct {
+	appStore *store.App
+	dispatch StageDispatcher
+
+	inputs     []textinput.Model
+	focusIndex int
+	spinner    spinner.Model
+
+	state      swapState
+	err        error
+	request    swapRequest
+	routes     []swapRoute
+	selected   int
+	signatures []string
+}
+
+// swapRequest is a validated swap form
+type swapRequest struct {
+	inToken  string
+	outToken string
+	amount   float64
+	slippage float64
+}
+
+// swapRoute is one quoted route of a project
+type swapRoute struct {
+	project pb.Project
+	route   *pb.QuoteRoute
+}
+
+type swapQuotesMsg struct {
+	routes []swapRoute
+	err    error
+}
+
+type swapSubmitMsg struct {
+	signatures []string
+	err        error
+}
+
+func newSwapModel(appStore *store.App) StageModel {
+	m := &swapModel{
+		appStore: appStore,
+		inputs:   make([]textinput.Model, swInputCount),
+		spinner:  spinner.New(spinner.WithSpinner(spinner.Points)),
+	}
+
+	for i := range m.inputs {
+		t := textinput.New()
+		switch i {
+		case swInToken:
+			t.Placeholder = "Input Token (symbol or mint, e.g. SOL)"
+		case swOutToken:
+			t.Placeholder = "Output Token (symbol or mint, e.g. USDC)"
+		case swAmount:
+			t.Placeholder = "Input Amount"
+		case swSlippage:
+			t.Placeholder = fmt.Sprintf("Slippage %% (default %v)", defaultSlippage)
+		}
+		m.inputs[i] = t
+	}
+	return m
+}
+
+func (m *swapModel) Init(dispatch StageDispatcher) tea.Cmd {
+	m.dispatch = dispatch
+	m.state = swInput
+	m.focusIndex = 0
+	m.err = nil
+	return tea.Batch(focusInputs(m.inputs, m.focusIndex), textinput.Blink)
+}
+
+func (m *swapModel) Update(msg tea.Msg) (Stage, StageModel, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case swapQuotesMsg:
+		if m.state != swQuoting {
+			return StageSwap, m, nil
+		}
+		m.state = swQuotes
+		m.err = msg.err
+		m.routes = msg.routes
+		m.selected = 0
+		if m.err == nil && len(m.routes) == 0 {
+			m.err = fmt.Errorf("no routes found from %v to %v", m.request.inToken, m.request.outToken)
+		}
+		return StageSwap, m, nil
+	case swapSubmitMsg:
+		if m.state != swSubmitting {
+			return StageSwap, m, nil
+		}
+		if msg.err != nil {
+			m.err = msg.err
+			m.state = swQuotes
+			return StageSwap, m, nil
+		}
+		m.signatures = msg.signatures
+		m.state = swDone
+		return StageSwap, m, nil
+	case spinner.TickMsg:
+		if m.state == swQuoting || m.state == swSubmitting {
+			m.spinner, cmd = m.spinner.Update(msg)
+		}
+		return StageSwap, m, cmd
+	}
+
+	switch m.state {
+	case swInput:
+		cmd, submitted := updateForm(msg, m.inputs, &m.focusIndex)
+		if submitted {
+			return StageSwap, m, m.quote()
+		}
+		return StageSwap, m, cmd
+	case swQuotes, swDone:
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch {
+			case m.state == swQuotes && key.Matches(msg, keys.ScrollUp):
+				if m.selected > 0 {
+					m.selected--
+				}
+			case m.state == swQuotes && key.Matches(msg, keys.ScrollDown):
+				if m.selected < len(m.routes)-1 {
+					m.selected++
+				}
+			case m.state == swQuotes && key.Matches(msg, keys.Submit) && len(m.routes) > 0:
+				return StageSwap, m, m.submit()
+			case m.state == swQuotes && key.Matches(msg, keys.Refresh):
+				return StageSwap, m, m.fetchQuotes()
+			case key.Matches(msg, keys.Edit):
+				m.state = swInput
+				m.err = nil
+				return StageSwap, m, focusInputs(m.inputs, m.focusIndex)
+			}
+		}
+	}
+	return StageSwap, m, nil
+}
+
+// quote validates the form and fetches quotes for it
+func (m *swapModel) quote() tea.Cmd {
+	request, err := m.swapRequest()
+	if err != nil {
+		m.err = err
+		return nil
+	}
+	m.request = request
+	return m.fetchQuotes()
+}
+
+func (m *swapModel) swapRequest() (swapRequest, error) {
+	inToken := strings.TrimSpace(m.inputs[swInToken].Value())
+	outToken := strings.TrimSpace(m.inputs[swOutToken].Value())
+	if inToken == "" || outToken == "" {
+		return swapRequest{}, errors.New("input and output tokens cannot be empty")
+	}
+	amount, err := parseAmount("amount", m.inputs[swAmount].Value())
+	if err != nil {
+		return swapRequest{}, err
+	}
+	slippage, err := parseSlippage(m.inputs[swSlippage].Value())
+	if err != nil {
+		return swapRequest{}, err
+	}
+
+	return swapRequest{
+		inToken:  inToken,
+		outToken: outToken,
+		amount:   amount,
+		slippage: slippage,
+	}, nil
+}
+
+func parseSlippage(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return defaultSlippage, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f < 0 || f >= 100 || math.IsNaN(f) {
+		return 0, fmt.Errorf("invalid slippage %q: expected a percentage below 100", s)
+	}
+	return f, nil
+}
+
+// fetchQuotes requests routes from every swap project, listing the best output first
+func (m *swapModel) fetchQuotes() tea.Cmd {
+	traderProvider, err := m.appStore.Connected()
+	if err != nil {
+		m.err = err
+		return nil
+	}
+
+	m.err = nil
+	m.state = swQuoting
+	request := m.request
+	timeout := queryTimeout(m.appStore)
+	dispatch := m.dispatch
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		response, err := traderProvider.GetQuotes(ctx, request.inToken, request.outToken, request.amount, request.slippage, routesPerQuote, swapProjects)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				err = fmt.Errorf("quotes timed out after %v: %w", timeout, err)
+			}
+			dispatch(swapQuotesMsg{err: err})
+			return
+		}
+
+		routes := make([]swapRoute, 0)
+		for _, quote := range response.Quotes {
+			for _, route := range quote.Routes {
+				routes = append(routes, swapRoute{project: quote.Project, route: route})
+			}
+		}
+		sort.SliceStable(routes, func(i, j int) bool {
+			return routes[i].route.OutAmount > routes[j].route.OutAmount
+		})
+		dispatch(swapQuotesMsg{routes: routes})
+	}()
+	return m.spinner.Tick
+}
+
+func (m *swapModel) submit() tea.Cmd {
+	settings := m.appStore.CurrentSettings()
+	if settings.PublicKey.IsZero() || len(settings.PrivateKey) == 0 {
+		m.err = errors.New("a private and public key are required to trade: set them from the settings stage")
+		return nil
+	}
+	traderProvider, err := m.appStore.Connected()
+	if err != nil {
+		m.err = err
+		return nil
+	}
+
+	m.err = nil
+	m.state = swSubmitting
+	request := m.routes[m.selected].request(settings.PublicKey.String(), m.request.slippage)
+	dispatch := m.dispatch
+	go func() {
+		ctx, cancel := submitContext()
+		defer cancel()
+		opts := provider.SubmitOpts{SubmitStrategy: pb.SubmitStrategy_P_ABORT_ON_FIRST_ERROR}
+		response, err := traderProvider.SubmitRouteTradeSwap(ctx, request, opts)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				err = fmt.Errorf("swap timed out after %v; its outcome is unknown, check balances before retrying: %w", submitTimeout, ctx.Err())
+			}
+			dispatch(swapSubmitMsg{err: err})
+			return
+		}
+
+		signatures := make([]string, 0, len(response.Transactions))
+		for _, tx := range response.Transactions {
+			if !tx.Submitted {
+				err := fmt.Errorf("swap failed: %v", tx.Error)
+				if len(signatures) > 0 {
+					err = fmt.Errorf("swap failed after submitting %v: %v", strings.Join(signatures, ", "), tx.Error)
+				}
+				dispatch(swapSubmitMsg{err: err})
+				return
+			}
+			signatures = append(signatures, tx.Signature)
+		}
+		dispatch(swapSubmitMsg{signatures: signatures})
+	}()
+	return m.spinner.Tick
+}
+
+// request builds the route swap for the quoted steps, bounding each step's output by the slippage
+func (r swapRoute) request(owner string, slippage float64) *pb.RouteTradeSwapRequest {
+	steps := make([]*pb.RouteStep, 0, len(r.route.Steps))
+	for i, step := range r.route.Steps {
+		outAmountMin := step.OutAmount * (1 - slippage/100)
+		if i == len(r.route.Steps)-1 && r.route.OutAmountMin > 0 {
+			outAmountMin = r.route.OutAmountMin
+		}
+		steps = append(steps, &pb.RouteStep{
+			InToken:      tokenOrAddress(step.InToken, step.InTokenAddress),
+			InAmount:     step.InAmount,
+			OutToken:     tokenOrAddress(step.OutToken, step.OutTokenAddress),
+			OutAmount:    step.OutAmount,
+			OutAmountMin: outAmountMin,
+			Project:      step.Project,
+		})
+	}
+	return &pb.RouteTradeSwapRequest{Project: r.project, OwnerAddress: owner, Steps: steps}
+}
+
+// tokenOrAddress prefers the mint address, which is unambiguous
+func tokenOrAddress(token, address string) string {
+	if address != "" {
+		return address
+	}
+	return token
+}
+
+// priceImpact sums the steps' price impact, reporting infinite if any step is
+func (r swapRoute) priceImpact() string {
+	total := 0.0
+	for _, step := range r.route.Steps {
+		if step.PriceImpactPercent == nil {
+			continue
+		}
+		if step.PriceImpactPercent.Infinity != common.Infinity_INF_NOT {
+			return "∞"
+		}
+		total += step.PriceImpactPercent.Percent
+	}
+	return fmt.Sprintf("%.4f%%", total)
+}
+
+// path lists the route's tokens with the project of each hop, e.g. SOL -(Raydium)-> USDC
+func (r swapRoute) path() string {
+	if len(r.route.Steps) == 0 {
+		return "-"
+	}
+
+	var b strings.Builder
+	b.WriteString(r.route.Steps[0].InToken)
+	for _, step := range r.route.Steps {
+		label := "?"
+		if step.Project != nil {
+			label = step.Project.Label
+		}
+		b.WriteString(fmt.Sprintf(" -(%v)-> %v", label, step.OutToken))
+	}
+	return b.String()
+}
+
+// CapturesKey also holds back while submitting, as leaving would drop the result and signatures
+func (m *swapModel) CapturesKey(msg tea.KeyMsg) bool {
+	if m.state == swSubmitting {
+		return key.Matches(msg, keys.Back)
+	}
+	return m.state == swInput && m.focusIndex < len(m.inputs) && capturesTextKey(msg)
+}
+
+// Leave returns loading quotes to the form: their result is dropped once the stage is left, so they are no longer
+// busy. Submitting swaps cannot be left.
+func (m *swapModel) Leave() {
+	if m.state == swQuoting {
+		m.state = swInput
+	}
+}
+
+func (m *swapModel) Busy() bool {
+	return m.state == swQuoting || m.state == swSubmitting
+}
+
+func (m *swapModel) Bindings() []key.Binding {
+	switch m.state {
+	case swQuotes:
+		return []key.Binding{keys.ScrollUp, keys.ScrollDown, keys.Submit, keys.Refresh, keys.Edit}
+	case swDone:
+		return []key.Binding{keys.Edit}
+	default:
+		return []key.Binding{keys.NextField, keys.PrevField, keys.Submit}
+	}
+}
+
+func (m *swapModel) View() string {
+	var b strings.Builder
+
+	switch m.state {
+	case swInput:
+		b.WriteString(inputsView(m.inputs, m.focusIndex))
+	case swQuoting:
+		b.WriteString(m.spinner.View())
+		b.WriteString(fmt.Sprintf(" fetching quotes for %v %v → %v…\n", formatFloat(m.request.amount), m.request.inToken, m.request.outToken))
+	case swQuotes, swSubmitting:
+		b.WriteString(m.routesView())
+		b.WriteRune('\n')
+		if m.state == swSubmitting {
+			b.WriteString(m.spinner.View())
+			b.WriteString(" submitting… (back is disabled until the result arrives)\n")
+		} else {
+			b.WriteString(helpStyle.Render(fmt.Sprintf("(%v to sign and submit the selected route • %v to requote • %v to edit)", keys.Submit.Help().Key, keys.Refresh.Help().Key, keys.Edit.Help().Key)))
+			b.WriteRune('\n')
+		}
+	case swDone:
+		route := m.routes[m.selected]
+		b.WriteString(fmt.Sprintf("swapped %v %v for ~%v %v via %v\n\n", formatFloat(m.request.amount), m.request.inToken, formatFloat(route.route.OutAmount), m.request.outToken, route.path()))
+		b.WriteString(statusStyle.Render(fmt.Sprintf("submitted: %v", strings.Join(m.signatures, ", "))))
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render(fmt.Sprintf("(%v to enter another swap)", keys.Edit.Help().Key)))
+		b.WriteRune('\n')
+	}
+
+	if m.err != nil {
+		b.WriteString(errorStyle.Render(m.err.Error()))
+		b.WriteRune('\n')
+	}
+	return b.String()
+}
+
+func (m *swapModel) routesView() string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("%v %v → %v • slippage %v%%\n\n", formatFloat(m.request.amount), m.request.inToken, m.request.outToken, formatFloat(m.request.slippage)))
+	for i, route := range m.routes {
+		cursor, style := "  ", noStyle
+		if i == m.selected {
+			cursor, style = "> ", focusedStyle
+		}
+		b.WriteString(style.Render(fmt.Sprintf("%v%-10v out: %v (min %v) • impact: %v", cursor, route.project, formatFloat(route.route.OutAmount), formatFloat(route.route.OutAmountMin), route.priceImpact())))
+		b.WriteRune('\n')
+		b.WriteString(helpStyle.Render(fmt.Sprintf("    %v", route.path())))
+		b.WriteRune('\n')
+	}
+	return b.String()
+}
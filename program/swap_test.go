@@ -0,0 +1,91 @@
+package program
+
+import (
+	"context"
+	"github.com/aspin/solana-trader-tui/store"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"testing"
+)
+
+func quoteRoute(outAmount float64) *pb.QuoteRoute {
+	return &pb.QuoteRoute{
+		InAmount:  1,
+		OutAmount: outAmount,
+		Steps:     []*pb.QuoteStep{{InToken: "SOL", OutToken: "USDC", InAmount: 1, OutAmount: outAmount}},
+	}
+}
+
+func TestSwapBestRouteFirst(t *testing.T) {
+	fake := store.NewFakeProvider()
+	fake.Quotes["SOL/USDC"] = []*pb.ProjectQuote{
+		{Project: pb.Project_P_RAYDIUM, Routes: []*pb.QuoteRoute{quoteRoute(20)}},
+		{Project: pb.Project_P_JUPITER, Routes: []*pb.QuoteRoute{quoteRoute(21)}},
+	}
+	d := newStageDriver(t, newSwapModel(newTestApp(t, fake)))
+	m := d.model.(*swapModel)
+
+	d.keys("SOL", "tab", "USDC", "tab", "1", "tab", "tab", "enter")
+	d.until("the quotes", func() bool {
+		return m.state == swQuotes
+	})
+	if len(m.routes) != 2 || m.routes[0].project != pb.Project_P_JUPITER {
+		t.Fatalf("routes = %v, want Jupiter's larger output first", m.routes)
+	}
+
+	d.keys("j", "enter")
+	d.until("the swap", func() bool {
+		return m.state == swDone
+	})
+	if len(fake.Swaps) != 1 || fake.Swaps[0].Project != pb.Project_P_RAYDIUM {
+		t.Fatalf("swaps = %v, want the selected Raydium route", fake.Swaps)
+	}
+	if outMin := fake.Swaps[0].Steps[0].OutAmountMin; outMin != 20*(1-defaultSlippage/100) {
+		t.Errorf("minimum output = %v, want the output less the default slippage", outMin)
+	}
+}
+
+func TestParseSlippage(t *testing.T) {
+	tests := []struct {
+		s     string
+		want  float64
+		valid bool
+	}{
+		{s: "", want: defaultSlippage, valid: true},
+		{s: "1", want: 1, valid: true},
+		{s: "0", want: 0, valid: true},
+		{s: "-1"},
+		{s: "100"},
+		{s: "NaN"},
+		{s: "Inf"},
+	}
+	for _, test := range tests {
+		got, err := parseSlippage(test.s)
+		if (err == nil) != test.valid || got != test.want {
+			t.Errorf("parseSlippage(%q) = %v, %v; want %v, valid = %v", test.s, got, err, test.want, test.valid)
+		}
+	}
+}
+
+// hangingQuotes never responds with quotes, until the context finishes
+type hangingQuotes struct {
+	*store.FakeProvider
+}
+
+func (p hangingQuotes) GetQuotes(ctx context.Context, inToken, outToken string, inAmount, slippage float64, limit int32, projects []pb.Project) (*pb.GetQuotesResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestSwapLeaveWhileQuoting(t *testing.T) {
+	d := newStageDriver(t, newSwapModel(newTestApp(t, hangingQuotes{store.NewFakeProvider()})))
+	m := d.model.(*swapModel)
+
+	d.keys("SOL", "tab", "USDC", "tab", "1", "tab", "tab", "enter")
+	if m.state != swQuoting {
+		t.Fatalf("state = %v, want quoting", m.state)
+	}
+	m.Leave()
+	if m.Busy() {
+		t.Error("still busy after leaving, so quitting from another stage asks to confirm")
+	}
+}
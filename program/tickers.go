@@ -0,0 +1,381 @@
+package program
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aspin/solana-trader-tui/store"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTickerInterval = 5 * time.Second
+	minTickerInterval     = time.Second
+)
+
+type tickersModel struct {
+	appStore *store.App
+	dispatch StageDispatcher
+
+	spinner spinner.Model
+
+	// refreshID invalidates ticks and responses from before the stage was last left
+	refreshID     int
+	loading       bool
+	active        bool
+	favoritesOnly bool
+	offset        int
+	updated       time.Time
+	err           error
+
+	rows    []tickerRow
+	session map[string]*tickerSession
+}
+
+type tickersMsg struct {
+	refreshID int
+	tickers   []*pb.Ticker
+	last      map[string]float64
+	err       error
+}
+
+type tickersTickMsg struct {
+	refreshID int
+}
+
+// tickerSession tracks a market's mid price since the stage was opened, as the API has no 24h statistics
+type tickerSession struct {
+	open float64
+	high float64
+	low  float64
+	mid  float64
+}
+
+type tickerRow struct {
+	ticker  *pb.Ticker
+	last    float64
+	hasLast bool
+	session tickerSession
+
+	// change is the direction of the mid price since the previous refresh: 1 up, -1 down, 0 unchanged
+	change int
+}
+
+func newTickersModel(appStore *store.App) StageModel {
+	return &tickersModel{
+		appStore: appStore,
+		spinner:  spinner.New(spinner.WithSpinner(spinner.Points)),
+	}
+}
+
+func (m *tickersModel) Init(dispatch StageDispatcher) tea.Cmd {
+	m.dispatch = dispatch
+	m.refreshID++
+	m.active = true
+	m.offset = 0
+	m.err = nil
+	m.rows = nil
+	m.session = make(map[string]*tickerSession)
+	return m.fetch()
+}
+
+func (m *tickersModel) interval() time.Duration {
+	interval := m.appStore.TickerInterval
+	if interval <= 0 {
+		return defaultTickerInterval
+	}
+	if interval < minTickerInterval {
+		return minTickerInterval
+	}
+	return interval
+}
+
+func (m *tickersModel) Update(msg tea.Msg) (Stage, StageModel, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tickersMsg:
+		if msg.refreshID != m.refreshID || !m.active {
+			return StageTickers, m, nil
+		}
+		m.loading = false
+		m.err = msg.err
+		if msg.err == nil {
+			m.apply(msg.tickers, msg.last)
+		}
+		return StageTickers, m, m.tick()
+	case tickersTickMsg:
+		if msg.refreshID != m.refreshID || !m.active || m.loading {
+			return StageTickers, m, nil
+		}
+		return StageTickers, m, m.fetch()
+	case spinner.TickMsg:
+		if m.loading {
+			m.spinner, cmd = m.spinner.Update(msg)
+		}
+		return StageTickers, m, cmd
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.FavoritesOnly):
+			m.favoritesOnly = !m.favoritesOnly
+			m.refreshID++
+			m.offset = 0
+			m.rows = nil
+			return StageTickers, m, m.fetch()
+		case key.Matches(msg, keys.Refresh) && !m.loading:
+			m.refreshID++
+			return StageTickers, m, m.fetch()
+		case key.Matches(msg, keys.ScrollUp):
+			if m.offset > 0 {
+				m.offset--
+			}
+		case key.Matches(msg, keys.ScrollDown):
+			if m.offset < len(m.rows)-1 {
+				m.offset++
+			}
+		}
+	}
+	return StageTickers, m, nil
+}
+
+func (m *tickersModel) tick() tea.Cmd {
+	refreshID := m.refreshID
+	return tea.Tick(m.interval(), func(time.Time) tea.Msg {
+		return tickersTickMsg{refreshID: refreshID}
+	})
+}
+
+// fetch requests tickers for every market or the favorites, with the last trade price of each favorite or of the markets
+// on screen, as last prices take a request per market
+func (m *tickersModel) fetch() tea.Cmd {
+	favorites := append([]string(nil), m.appStore.Favorites...)
+	if m.favoritesOnly && len(favorites) == 0 {
+		m.loading = false
+		m.err = errors.New("no favorite markets: star some from the markets stage")
+		return nil
+	}
+	traderProvider, err := m.appStore.Connected()
+	if err != nil {
+		m.loading = false
+		m.err = err
+		return nil
+	}
+
+	m.loading = true
+	refreshID := m.refreshID
+	project := m.appStore.CurrentSettings().Project
+	favoritesOnly := m.favoritesOnly
+	offset, pageSize := m.offset, m.pageSize()
+	timeout := queryTimeout(m.appStore)
+	dispatch := m.dispatch
+	go func() {
+		// a hung fetch would stop the refresh loop, which only ticks again once the response arrives
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if !favoritesOnly {
+			response, err := traderProvider.GetTickers(ctx, "", project)
+			if err != nil {
+				dispatch(tickersMsg{refreshID: refreshID, err: err})
+				return
+			}
+
+			markets := make([]string, 0, len(response.Tickers))
+			for _, ticker := range response.Tickers {
+				markets = append(markets, ticker.Market)
+			}
+			sort.Strings(markets)
+			markets = pageOf(markets, offset, pageSize)
+			dispatch(tickersMsg{refreshID: refreshID, tickers: response.Tickers, last: lastPrices(ctx, traderProvider, markets, project)})
+			return
+		}
+
+		tickers := make([]*pb.Ticker, 0, len(favorites))
+		for _, market := range favorites {
+			response, err := traderProvider.GetTickers(ctx, market, project)
+			if err != nil {
+				dispatch(tickersMsg{refreshID: refreshID, err: fmt.Errorf("%v: %w", market, err)})
+				return
+			}
+			tickers = append(tickers, response.Tickers...)
+		}
+		dispatch(tickersMsg{refreshID: refreshID, tickers: tickers, last: lastPrices(ctx, traderProvider, favorites, project)})
+	}()
+	return m.spinner.Tick
+}
+
+// pageOf is the page of markets starting at offset, or every market if no rows fit on screen
+func pageOf(markets []string, offset, pageSize int) []string {
+	if pageSize < 1 {
+		return markets
+	}
+	if offset > len(markets) {
+		offset = len(markets)
+	}
+	end := offset + pageSize
+	if end > len(markets) {
+		end = len(markets)
+	}
+	return markets[offset:end]
+}
+
+// lastPrices is the price of the latest trade in each market; markets whose trades cannot be fetched are left out
+func lastPrices(ctx context.Context, traderProvider store.TraderProvider, markets []string, project pb.Project) map[string]float64 {
+	last := make(map[string]float64)
+	for _, market := range markets {
+		trades, err := traderProvider.GetTrades(ctx, market, 1, project)
+		if err == nil && len(trades.Trades) > 0 {
+			last[market] = trades.Trades[0].FillPrice
+		}
+	}
+	return last
+}
+
+func (m *tickersModel) apply(tickers []*pb.Ticker, last map[string]float64) {
+	previous := make(map[string]float64, len(m.rows))
+	for _, row := range m.rows {
+		previous[row.ticker.Market] = row.session.mid
+	}
+
+	rows := make([]tickerRow, 0, len(tickers))
+	for _, ticker := range tickers {
+		mid := tickerMid(ticker)
+		session, ok := m.session[ticker.Market]
+		if !ok {
+			session = &tickerSession{open: mid, high: mid, low: mid}
+			m.session[ticker.Market] = session
+		}
+		session.mid = mid
+		if mid > session.high {
+			session.high = mid
+		}
+		if mid < session.low || session.low == 0 {
+			session.low = mid
+		}
+
+		row := tickerRow{ticker: ticker, session: *session}
+		row.last, row.hasLast = last[ticker.Market]
+		if prev, ok := previous[ticker.Market]; ok {
+			switch {
+			case mid > prev:
+				row.change = 1
+			case mid < prev:
+				row.change = -1
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].ticker.Market < rows[j].ticker.Market
+	})
+	m.rows = rows
+	m.updated = time.Now()
+	if m.offset >= len(m.rows) {
+		m.offset = 0
+	}
+}
+
+// tickerMid is the mid price, or whichever side is quoted
+func tickerMid(ticker *pb.Ticker) float64 {
+	return midPrice(ticker.Bid, ticker.Ask)
+}
+
+func (m *tickersModel) Leave() {
+	m.active = false
+	m.refreshID++
+}
+
+// pageSize is the number of rows on screen, leaving room for the status, header and help lines
+func (m *tickersModel) pageSize() int {
+	return m.appStore.UI.WindowHeight - 8
+}
+
+func (m *tickersModel) Bindings() []key.Binding {
+	return []key.Binding{keys.ScrollUp, keys.ScrollDown, keys.FavoritesOnly, keys.Refresh}
+}
+
+func (m *tickersModel) View() string {
+	var b strings.Builder
+
+	scope := "all markets"
+	if m.favoritesOnly {
+		scope = "favorites"
+	}
+	status := fmt.Sprintf("%v • refreshing every %v • SESS columns track the mid price since opening, as the API has no 24h stats", scope, m.interval())
+	if !m.updated.IsZero() {
+		status += fmt.Sprintf(" • updated %v", m.updated.Format("15:04:05"))
+	}
+	if m.loading {
+		status += " " + m.spinner.View()
+	}
+	b.WriteString(helpStyle.Render(status))
+	b.WriteString("\n\n")
+
+	if m.err != nil {
+		b.WriteString(errorStyle.Render(m.err.Error()))
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(fmt.Sprintf("%-14v %12v %12v %12v %10v %12v %11v %12v %12v\n", "MARKET", "BID", "ASK", "SPREAD", "BPS", "LAST", "SESS CHG %", "SESS HIGH", "SESS LOW"))
+
+	height := m.pageSize()
+	if height < 1 {
+		height = len(m.rows)
+	}
+	end := m.offset + height
+	if end > len(m.rows) {
+		end = len(m.rows)
+	}
+
+	for _, row := range m.rows[m.offset:end] {
+		b.WriteString(tickerRowStyle(row.change).Render(tickerRowView(row)))
+		b.WriteRune('\n')
+	}
+	if len(m.rows) > height {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("%v-%v of %v markets", m.offset+1, end, len(m.rows))))
+		b.WriteRune('\n')
+	}
+	return b.String()
+}
+
+func tickerRowView(row tickerRow) string {
+	ticker := row.ticker
+
+	spread, bps := "-", "-"
+	if ticker.Bid > 0 && ticker.Ask > 0 {
+		// %g trims the float noise left by the subtraction
+		spread = fmt.Sprintf("%.8g", ticker.Ask-ticker.Bid)
+		bps = fmt.Sprintf("%.2f", (ticker.Ask-ticker.Bid)/row.session.mid*10000)
+	}
+
+	change := "-"
+	if row.session.open > 0 {
+		change = fmt.Sprintf("%+.2f", (row.session.mid-row.session.open)/row.session.open*100)
+	}
+
+	last := "-"
+	if row.hasLast {
+		last = formatFloat(row.last)
+	}
+	return fmt.Sprintf("%-14v %12v %12v %12v %10v %12v %11v %12v %12v",
+		ticker.Market, formatFloat(ticker.Bid), formatFloat(ticker.Ask), spread, bps, last, change, formatFloat(row.session.high), formatFloat(row.session.low))
+}
+
+func tickerRowStyle(change int) lipgloss.Style {
+	switch change {
+	case 1:
+		return bidStyle
+	case -1:
+		return askStyle
+	default:
+		return noStyle
+	}
+}
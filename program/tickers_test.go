@@ -0,0 +1,86 @@
+package program
+
+import (
+	"context"
+	"errors"
+	"github.com/aspin/solana-trader-tui/store"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"strings"
+	"testing"
+	"time"
+)
+
+// hangingTickers never responds with tickers until the context finishes
+type hangingTickers struct {
+	*store.FakeProvider
+}
+
+func (p hangingTickers) GetTickers(ctx context.Context, market string, project pb.Project) (*pb.GetTickersResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func newFakeWithOrderbook(market string, bid, ask float64) *store.FakeProvider {
+	fake := store.NewFakeProvider()
+	fake.Orderbooks[market] = &pb.GetOrderbookResponse{
+		Market: market,
+		Bids:   []*pb.OrderbookItem{{Price: bid, Size: 1}},
+		Asks:   []*pb.OrderbookItem{{Price: ask, Size: 1}},
+	}
+	return fake
+}
+
+func TestTickersAllMarkets(t *testing.T) {
+	fake := newFakeWithOrderbook("SOL/USDC", 9, 11)
+	fake.Trades["SOL/USDC"] = []*pb.Trade{{FillPrice: 10.5}, {FillPrice: 10}}
+	d := newStageDriver(t, newTickersModel(newTestApp(t, fake)))
+	m := d.model.(*tickersModel)
+
+	d.until("the tickers", func() bool {
+		return !m.loading
+	})
+	if len(m.rows) != 1 || m.rows[0].session.mid != 10 {
+		t.Fatalf("rows = %v, want SOL/USDC with a mid of 10", m.rows)
+	}
+	if !m.rows[0].hasLast || m.rows[0].last != 10.5 {
+		t.Errorf("last = %v, %v; want the latest trade price", m.rows[0].last, m.rows[0].hasLast)
+	}
+	if view := m.View(); !strings.Contains(view, "LAST") || !strings.Contains(view, "SESS HIGH") {
+		t.Errorf("view does not label the last and session columns:\n%v", view)
+	}
+	if _, ok := d.model.(BusyReporter); ok {
+		t.Error("periodic refreshes are reported as work lost by quitting")
+	}
+}
+
+func TestTickersTimeout(t *testing.T) {
+	app := newTestApp(t, hangingTickers{newFakeWithOrderbook("SOL/USDC", 9, 11)})
+	app.QueryTimeout = 50 * time.Millisecond
+	d := newStageDriver(t, newTickersModel(app))
+	m := d.model.(*tickersModel)
+
+	d.until("the fetch to time out", func() bool {
+		return !m.loading
+	})
+	if !errors.Is(m.err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want the fetch timed out", m.err)
+	}
+}
+
+func TestPageOf(t *testing.T) {
+	markets := []string{"A", "B", "C", "D"}
+	tests := []struct {
+		offset, pageSize int
+		want             string
+	}{
+		{offset: 0, pageSize: 2, want: "A B"},
+		{offset: 3, pageSize: 2, want: "D"},
+		{offset: 5, pageSize: 2, want: ""},
+		{offset: 1, pageSize: 0, want: "A B C D"},
+	}
+	for _, test := range tests {
+		if got := strings.Join(pageOf(markets, test.offset, test.pageSize), " "); got != test.want {
+			t.Errorf("pageOf(%v, %v) = %q, want %q", test.offset, test.pageSize, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,382 @@
+package program
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aspin/solana-trader-tui/store"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultTapeSize = 200
+
+const (
+	tsMarket = iota
+	tsBufferSize
+	tsMinSize
+)
+
+type timeSalesModel struct {
+	appStore *store.App
+	dispatch StageDispatcher
+
+	inputs     []textinput.Model
+	focusIndex int
+
+	streaming bool
+	streamID  int
+	cancel    context.CancelFunc
+	err       error
+
+	market   string
+	slot     int64
+	size     int
+	minSize  float64
+	filtered int
+
+	// tape is most recent first; frozen is what is shown while paused
+	tape   []tapeTrade
+	frozen []tapeTrade
+	paused bool
+	missed int
+	offset int
+}
+
+// tapeTrade is a trade as received; the stream does not carry trade times
+type tapeTrade struct {
+	received time.Time
+	side     pb.Side
+	price    float64
+	size     float64
+}
+
+func (t tapeTrade) notional() float64 {
+	return t.price * t.size
+}
+
+type tradesStreamMsg struct {
+	streamID int
+	update   *pb.GetTradesStreamResponse
+}
+
+type tradesStreamErrMsg struct {
+	streamID int
+	err      error
+}
+
+func newTimeSalesModel(appStore *store.App) StageModel {
+	m := &timeSalesModel{
+		appStore: appStore,
+		inputs:   make([]textinput.Model, 3),
+	}
+
+	for i := range m.inputs {
+		t := textinput.New()
+		switch i {
+		case tsMarket:
+			t.Placeholder = "Market Name (e.g. SOL/USDC) or Public Key"
+		case tsBufferSize:
+			t.Placeholder = fmt.Sprintf("Buffer Size (default %v trades)", defaultTapeSize)
+			t.Validate = validateTapeSize
+		case tsMinSize:
+			t.Placeholder = "Minimum Trade Size (default 0)"
+		}
+		m.inputs[i] = t
+	}
+	return m
+}
+
+func (m *timeSalesModel) Init(dispatch StageDispatcher) tea.Cmd {
+	m.dispatch = dispatch
+	m.focusIndex = 0
+	m.err = nil
+	return tea.Batch(focusInputs(m.inputs, m.focusIndex), textinput.Blink)
+}
+
+func (m *timeSalesModel) Update(msg tea.Msg) (Stage, StageModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tradesStreamMsg:
+		if msg.streamID == m.streamID && m.streaming {
+			m.apply(msg.update, time.Now())
+		}
+		return StageTimeSales, m, nil
+	case tradesStreamErrMsg:
+		if msg.streamID == m.streamID && m.streaming {
+			m.stop()
+			m.err = msg.err
+			return StageTimeSales, m, focusInputs(m.inputs, m.focusIndex)
+		}
+		return StageTimeSales, m, nil
+	}
+
+	if m.streaming {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch {
+			case key.Matches(msg, keys.Edit):
+				m.stop()
+				return StageTimeSales, m, focusInputs(m.inputs, m.focusIndex)
+			case key.Matches(msg, keys.Pause):
+				m.togglePause()
+			case key.Matches(msg, keys.ScrollUp):
+				if m.offset > 0 {
+					m.offset--
+				}
+			case key.Matches(msg, keys.ScrollDown):
+				if m.offset < len(m.shown())-1 {
+					m.offset++
+				}
+			}
+		}
+		return StageTimeSales, m, nil
+	}
+
+	if msg, ok := msg.(marketPickedMsg); ok {
+		m.inputs[tsMarket].SetValue(msg.market)
+		return StageTimeSales, m, nil
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok && m.focusIndex == tsMarket && key.Matches(msg, keys.PickMarket) {
+		stage, cmd := pickMarket()
+		return stage, m, cmd
+	}
+	cmd, submitted := updateForm(msg, m.inputs, &m.focusIndex)
+	if submitted {
+		m.start()
+		return StageTimeSales, m, nil
+	}
+	return StageTimeSales, m, cmd
+}
+
+func (m *timeSalesModel) start() {
+	market := strings.TrimSpace(m.inputs[tsMarket].Value())
+	if market == "" {
+		m.err = errors.New("market cannot be empty")
+		return
+	}
+	if err := m.inputs[tsBufferSize].Err; err != nil {
+		m.err = err
+		return
+	}
+	size := int(parseTapeSize(m.inputs[tsBufferSize].Value()))
+	minSize, err := parseMinSize(m.inputs[tsMinSize].Value())
+	if err != nil {
+		m.err = err
+		return
+	}
+	provider, err := m.appStore.Connected()
+	if err != nil {
+		m.err = err
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.streamID++
+	m.cancel = cancel
+	m.streaming = true
+	m.err = nil
+	m.market = market
+	m.slot = 0
+	m.size = size
+	m.minSize = minSize
+	m.filtered = 0
+	m.tape = nil
+	m.frozen = nil
+	m.paused = false
+	m.missed = 0
+	m.offset = 0
+
+	streamID := m.streamID
+	project := m.appStore.CurrentSettings().Project
+	dispatch := m.dispatch
+	go func() {
+		stream, err := provider.GetTradesStream(ctx, market, uint32(size), project)
+		if err != nil {
+			dispatch(tradesStreamErrMsg{streamID: streamID, err: err})
+			return
+		}
+
+		for {
+			update, err := stream()
+			if err != nil {
+				if ctx.Err() == nil {
+					dispatch(tradesStreamErrMsg{streamID: streamID, err: err})
+				}
+				return
+			}
+			dispatch(tradesStreamMsg{streamID: streamID, update: update})
+		}
+	}()
+}
+
+// stop cancels the stream's context, ending the receiving goroutine
+func (m *timeSalesModel) stop() {
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+	m.streaming = false
+}
+
+// apply adds the update's trades at or above the minimum size to the top of the tape, dropping the oldest beyond the buffer size
+func (m *timeSalesModel) apply(update *pb.GetTradesStreamResponse, now time.Time) {
+	m.slot = update.Slot
+	if update.Trades == nil {
+		return
+	}
+
+	added := make([]tapeTrade, 0, len(update.Trades.Trades))
+	for _, trade := range update.Trades.Trades {
+		if trade.Size < m.minSize {
+			m.filtered++
+			continue
+		}
+		added = append(added, tapeTrade{received: now, side: trade.Side, price: trade.FillPrice, size: trade.Size})
+	}
+
+	m.tape = append(added, m.tape...)
+	if len(m.tape) > m.size {
+		m.tape = m.tape[:m.size]
+	}
+	if m.paused {
+		m.missed += len(added)
+	} else if m.offset > 0 {
+		// keep the rows being read in place as new trades arrive above them
+		m.offset += len(added)
+		if m.offset >= len(m.tape) {
+			m.offset = len(m.tape) - 1
+		}
+	}
+}
+
+// togglePause freezes the shown tape while trades keep being buffered, and jumps back to the latest trade on resume
+func (m *timeSalesModel) togglePause() {
+	m.paused = !m.paused
+	if m.paused {
+		m.frozen = append([]tapeTrade(nil), m.tape...)
+		m.missed = 0
+		return
+	}
+	m.frozen = nil
+	m.offset = 0
+}
+
+func (m *timeSalesModel) shown() []tapeTrade {
+	if m.paused {
+		return m.frozen
+	}
+	return m.tape
+}
+
+func validateTapeSize(s string) error {
+	if s == "" {
+		return nil
+	}
+	if _, err := strconv.ParseUint(s, 10, 32); err != nil {
+		return fmt.Errorf("invalid buffer size: %v", s)
+	}
+	return nil
+}
+
+func parseTapeSize(s string) uint32 {
+	size, err := strconv.ParseUint(s, 10, 32)
+	if err != nil || size == 0 {
+		return defaultTapeSize
+	}
+	return uint32(size)
+}
+
+func parseMinSize(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f < 0 || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("invalid minimum size %q: expected a non-negative number", s)
+	}
+	return f, nil
+}
+
+func (m *timeSalesModel) Leave() {
+	m.stop()
+}
+
+func (m *timeSalesModel) CapturesKey(msg tea.KeyMsg) bool {
+	return !m.streaming && m.focusIndex < len(m.inputs) && capturesTextKey(msg)
+}
+
+func (m *timeSalesModel) Busy() bool {
+	return m.streaming
+}
+
+func (m *timeSalesModel) Bindings() []key.Binding {
+	if m.streaming {
+		return []key.Binding{keys.Pause, keys.ScrollUp, keys.ScrollDown, keys.Edit}
+	}
+	return []key.Binding{keys.NextField, keys.PrevField, keys.Submit, keys.PickMarket}
+}
+
+func (m *timeSalesModel) View() string {
+	var b strings.Builder
+
+	if !m.streaming {
+		b.WriteString(inputsView(m.inputs, m.focusIndex))
+		if m.err != nil {
+			b.WriteString(errorStyle.Render(m.err.Error()))
+			b.WriteRune('\n')
+		}
+		return b.String()
+	}
+
+	status := fmt.Sprintf("%v • slot %v • %v/%v trades", m.market, m.slot, len(m.tape), m.size)
+	if m.minSize > 0 {
+		status += fmt.Sprintf(" • min size %v (%v hidden)", formatFloat(m.minSize), m.filtered)
+	}
+	b.WriteString(status)
+	if m.paused {
+		b.WriteString(confirmStyle.Render(fmt.Sprintf(" • paused, %v new", m.missed)))
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString(fmt.Sprintf("%-12v %-4v %14v %14v %14v\n", "TIME", "SIDE", "PRICE", "SIZE", "NOTIONAL"))
+
+	trades := m.shown()
+	// leave room for the status, header and help lines
+	height := m.appStore.UI.WindowHeight - 9
+	if height < 1 {
+		height = len(trades)
+	}
+	end := m.offset + height
+	if end > len(trades) {
+		end = len(trades)
+	}
+	if m.offset < end {
+		for _, trade := range trades[m.offset:end] {
+			b.WriteString(tapeTradeView(trade))
+			b.WriteRune('\n')
+		}
+	}
+	if len(trades) == 0 {
+		b.WriteString(helpStyle.Render("waiting for trades…"))
+		b.WriteRune('\n')
+	}
+
+	b.WriteRune('\n')
+	b.WriteString(helpStyle.Render(fmt.Sprintf("(times are when trades were received • %v to pause/resume • %v to stop and change market)", keys.Pause.Help().Key, keys.Edit.Help().Key)))
+	return b.String()
+}
+
+func tapeTradeView(trade tapeTrade) string {
+	style, side := bidStyle, "bid"
+	if trade.side == pb.Side_S_ASK {
+		style, side = askStyle, "ask"
+	}
+	return style.Render(fmt.Sprintf("%-12v %-4v %14v %14v %14v",
+		trade.received.Format("15:04:05.000"), side, formatFloat(trade.price), formatFloat(trade.size), fmt.Sprintf("%.2f", trade.notional())))
+}
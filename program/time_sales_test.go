@@ -0,0 +1,71 @@
+package program
+
+import (
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"testing"
+	"time"
+)
+
+func tradesUpdate(slot int64, sizes ...float64) *pb.GetTradesStreamResponse {
+	trades := make([]*pb.Trade, 0, len(sizes))
+	for _, size := range sizes {
+		trades = append(trades, &pb.Trade{Side: pb.Side_S_BID, FillPrice: 10, Size: size})
+	}
+	return &pb.GetTradesStreamResponse{Slot: slot, Trades: &pb.GetTradesResponse{Trades: trades}}
+}
+
+func TestTimeSalesTape(t *testing.T) {
+	m := &timeSalesModel{size: 3, minSize: 1}
+	now := time.Now()
+
+	m.apply(tradesUpdate(1, 0.5, 1, 2), now)
+	if len(m.tape) != 2 || m.filtered != 1 {
+		t.Fatalf("tape = %v, filtered = %v; want the trade below the minimum size filtered", m.tape, m.filtered)
+	}
+
+	m.apply(tradesUpdate(2, 3, 4), now)
+	if len(m.tape) != 3 || m.tape[0].size != 3 || m.tape[2].size != 1 {
+		t.Errorf("tape = %v, want the newest trades first and the oldest dropped beyond the buffer size", m.tape)
+	}
+	if m.slot != 2 {
+		t.Errorf("slot = %v, want the latest update's", m.slot)
+	}
+}
+
+func TestTimeSalesPause(t *testing.T) {
+	m := &timeSalesModel{size: 10}
+	now := time.Now()
+	m.apply(tradesUpdate(1, 1), now)
+
+	m.togglePause()
+	m.apply(tradesUpdate(2, 2, 3), now)
+	if len(m.shown()) != 1 || m.missed != 2 {
+		t.Errorf("shown = %v, missed = %v while paused; want the frozen tape and the missed count", m.shown(), m.missed)
+	}
+
+	m.togglePause()
+	if len(m.shown()) != 3 || m.offset != 0 {
+		t.Errorf("shown = %v, offset = %v after resuming; want the whole tape from the latest trade", m.shown(), m.offset)
+	}
+}
+
+func TestParseMinSize(t *testing.T) {
+	tests := []struct {
+		s     string
+		want  float64
+		valid bool
+	}{
+		{s: "", want: 0, valid: true},
+		{s: " 2.5 ", want: 2.5, valid: true},
+		{s: "-1"},
+		{s: "abc"},
+		{s: "NaN"},
+		{s: "Inf"},
+	}
+	for _, test := range tests {
+		got, err := parseMinSize(test.s)
+		if (err == nil) != test.valid || got != test.want {
+			t.Errorf("parseMinSize(%q) = %v, %v; want %v, valid = %v", test.s, got, err, test.want, test.valid)
+		}
+	}
+}
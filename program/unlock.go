@@ -0,0 +1,116 @@
+package program
+
+import (
+	"fmt"
+	"github.com/aspin/solana-trader-tui/store"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"strings"
+)
+
+type unlockModel struct {
+	err    error
+	status string
+
+	input textinput.Model
+
+	appStore *store.App
+	dispatch StageDispatcher
+}
+
+func newUnlockModel(appStore *store.App) StageModel {
+	t := textinput.New()
+	t.Placeholder = "Passphrase"
+	t.EchoMode = textinput.EchoPassword
+	t.EchoCharacter = '*'
+
+	return &unlockModel{
+		input:    t,
+		appStore: appStore,
+	}
+}
+
+func (m *unlockModel) Init(dispatch StageDispatcher) tea.Cmd {
+	m.dispatch = dispatch
+	m.input.PromptStyle = focusedStyle
+	return m.input.Focus()
+}
+
+func (m *unlockModel) Update(msg tea.Msg) (Stage, StageModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, keys.Submit) {
+			m.err = nil
+			m.unlock(m.input.Value())
+			return StageUnlock, m, nil
+		}
+	case statusMsg:
+		m.status = msg.status
+	case statusErrMsg:
+		m.status = ""
+		m.err = msg.err
+	case statusDoneMsg:
+		m.input.SetValue("")
+		if m.appStore.NeedsInit() {
+			return StageSettings, m, nil
+		}
+		return StageMenu, m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return StageUnlock, m, cmd
+}
+
+func (m *unlockModel) CapturesKey(msg tea.KeyMsg) bool {
+	return capturesDisconnectedBack(m.appStore, msg) || capturesTextKey(msg)
+}
+
+func (m *unlockModel) Bindings() []key.Binding {
+	return []key.Binding{keys.Submit}
+}
+
+func (m *unlockModel) unlock(passphrase string) {
+	dispatch := m.dispatch
+	go func() {
+		dispatch(statusMsg{status: "unlocking..."})
+		err := m.appStore.Unlock(passphrase)
+		if err != nil {
+			dispatch(statusErrMsg{err: err})
+			return
+		}
+
+		if !m.appStore.NeedsInit() {
+			dispatch(statusMsg{status: "connecting..."})
+			err = m.appStore.Connect()
+			if err != nil {
+				dispatch(statusErrMsg{err: fmt.Errorf("could not connect API client: %w", err)})
+				return
+			}
+		}
+		dispatch(statusDoneMsg{})
+	}()
+}
+
+func (m *unlockModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("The private key for profile %v is encrypted.\n\n", m.appStore.Profile))
+	b.WriteString(m.input.View())
+	b.WriteString("\n\n")
+
+	if m.status != "" {
+		b.WriteString(statusStyle.Render(m.status))
+		b.WriteRune('\n')
+	}
+
+	if m.err != nil {
+		b.WriteString(errorStyle.Render(m.err.Error()))
+		b.WriteRune('\n')
+	}
+
+	b.WriteString(disconnectedView(m.appStore))
+	b.WriteString(helpStyle.Render("(enter to unlock)"))
+	return b.String()
+}
@@ -1,21 +1,50 @@
 package store
 
 import (
-	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/bloXroute-Labs/solana-trader-client-go/provider"
 	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
 	"github.com/gagliardetto/solana-go"
 	"log"
-	"os"
 	"sync"
+	"time"
 )
 
+// ErrNotConnected is returned while the active profile has no API client, e.g. before it is unlocked
+var ErrNotConnected = errors.New("not connected: unlock or finish setting up the active profile")
+
 type App struct {
-	m        sync.Mutex
-	Err      error
-	UI       UI
-	Settings Settings
-	Provider *provider.GRPCClient
+	m          sync.Mutex
+	Err        error
+	UI         UI
+	ConfigFile string
+	Keys       map[string][]string
+	Favorites  []string
+	Profile    string
+	Settings   Settings
+	Provider   TraderProvider
+
+	// TickerInterval is how often the tickers stage refreshes; zero uses the stage's default
+	TickerInterval time.Duration
+
+	// QueryTimeout bounds list queries; zero uses the component's default
+	QueryTimeout time.Duration
+
+	// PollInterval is how often list results are refreshed; zero uses the component's default and negative disables polling
+	PollInterval time.Duration
+
+	// OrderStatusMarkets are watched for order status notifications; favorites are watched when empty
+	OrderStatusMarkets []string
+
+	// History is the list query history of each stage, most recent first, kept in the state file
+	History map[string][][]string
+
+	// lockedKey is the encrypted private key of the active profile awaiting Unlock
+	lockedKey *encryptedKey
+
+	// profiles holds every profile; the active one is only synced from Settings on save or switch
+	profiles []*profile
 }
 
 type UI struct {
@@ -29,71 +58,240 @@ type Settings struct {
 	PublicKey         solana.PublicKey
 	OpenOrdersAddress solana.PublicKey
 	Project           pb.Project
+	Transport         Transport
+	Network           Network
+	Endpoint          string
+	UseTLS            bool
+
+	// Passphrase encrypts the private key when saving; it is never written to disk
+	Passphrase string
 }
 
+// NewFromFile loads the config file, falling back to empty settings (to be filled in from the settings stage) if it cannot be loaded
 func NewFromFile(filename string) *App {
-	b, err := os.ReadFile(filename)
+	a, err := LoadFromFile(filename)
 	if err != nil {
-		log.Printf("could not read file (%v): %v", filename, err)
-		return &App{}
-	}
-
-	m := struct {
-		AuthHeader        string           `json:"authHeader"`
-		PrivateKey        string           `json:"privateKey"`
-		PublicKey         solana.PublicKey `json:"publicKey"`
-		OpenOrdersAddress solana.PublicKey `json:"openOrdersAddress"`
-		Project           string           `json:"project"`
-	}{}
-	err = json.Unmarshal(b, &m)
+		log.Printf("%v", err)
+		a = &App{ConfigFile: filename, Profile: defaultProfile}
+		a.loadState()
+	}
+	return a
+}
+
+// LoadFromFile loads the config file, activating the profile it marks as active
+func LoadFromFile(filename string) (*App, error) {
+	c, err := readConfig(filename)
 	if err != nil {
-		log.Printf("could not unmarshal json: %v, bytes: %v", err, string(b))
-		return &App{}
+		return nil, fmt.Errorf("could not read config file (%v): %w", filename, err)
 	}
 
-	s := Settings{
-		AuthHeader:        m.AuthHeader,
-		PublicKey:         m.PublicKey,
-		OpenOrdersAddress: m.OpenOrdersAddress,
+	profiles := make([]*profile, 0, len(c.Profiles))
+	for _, pc := range c.Profiles {
+		s, err := settingsFromConfig(pc)
+		if err != nil {
+			return nil, fmt.Errorf("could not load profile %v from config file (%v): %w", pc.Name, filename, err)
+		}
+		profiles = append(profiles, &profile{name: pc.Name, settings: s, lockedKey: pc.EncryptedKey})
 	}
-	s.PrivateKey, err = solana.PrivateKeyFromBase58(m.PrivateKey)
-	if err != nil {
-		log.Printf("could not deserialize private key: %v", err)
-		return &App{}
+
+	var tickerInterval time.Duration
+	if c.TickerInterval != "" {
+		if tickerInterval, err = time.ParseDuration(c.TickerInterval); err != nil {
+			return nil, fmt.Errorf("invalid ticker interval in config file (%v): %w", filename, err)
+		}
+	}
+
+	var queryTimeout time.Duration
+	if c.QueryTimeout != "" {
+		if queryTimeout, err = time.ParseDuration(c.QueryTimeout); err != nil {
+			return nil, fmt.Errorf("invalid query timeout in config file (%v): %w", filename, err)
+		}
 	}
 
-	project, ok := pb.Project_value[m.Project]
-	if !ok {
-		log.Printf("could not deserialize project: %v, value: %v", err, m.Project)
-		return &App{}
+	var pollInterval time.Duration
+	if c.PollInterval != "" {
+		if pollInterval, err = time.ParseDuration(c.PollInterval); err != nil {
+			return nil, fmt.Errorf("invalid poll interval in config file (%v): %w", filename, err)
+		}
 	}
-	s.Project = pb.Project(project)
 
-	return &App{
-		Settings: s,
+	a := &App{
+		ConfigFile:         filename,
+		Keys:               c.Keys,
+		Favorites:          c.Favorites,
+		Profile:            defaultProfile,
+		TickerInterval:     tickerInterval,
+		QueryTimeout:       queryTimeout,
+		PollInterval:       pollInterval,
+		OrderStatusMarkets: c.OrderStatusMarkets,
+		profiles:           profiles,
 	}
+	a.loadState()
+	if len(profiles) == 0 {
+		return a, nil
+	}
+
+	active := profiles[0]
+	for _, p := range profiles {
+		if p.name == c.ActiveProfile {
+			active = p
+		}
+	}
+	a.load(active)
+	return a, nil
+}
+
+// Save writes all profiles back to the config file
+func (a *App) Save() error {
+	if a.ConfigFile == "" {
+		return errors.New("no config file configured")
+	}
+
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	a.storeActive()
+	c := configFile{
+		ActiveProfile:      a.Profile,
+		Profiles:           make([]profileConfig, 0, len(a.profiles)),
+		Keys:               a.Keys,
+		Favorites:          a.Favorites,
+		OrderStatusMarkets: a.OrderStatusMarkets,
+	}
+	if a.TickerInterval > 0 {
+		c.TickerInterval = a.TickerInterval.String()
+	}
+	if a.QueryTimeout > 0 {
+		c.QueryTimeout = a.QueryTimeout.String()
+	}
+	if a.PollInterval != 0 {
+		c.PollInterval = a.PollInterval.String()
+	}
+	for _, p := range a.profiles {
+		pc, err := configFromSettings(p.name, p.settings, p.lockedKey)
+		if err != nil {
+			return fmt.Errorf("could not serialize profile %v: %w", p.name, err)
+		}
+		c.Profiles = append(c.Profiles, pc)
+	}
+	return writeConfig(a.ConfigFile, c)
+}
+
+// NeedsUnlock indicates the config file holds an encrypted private key that has not been decrypted yet
+func (a *App) NeedsUnlock() bool {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	return a.lockedKey != nil
+}
+
+// Unlock decrypts the private key with passphrase, keeping the passphrase to re-encrypt on save
+func (a *App) Unlock(passphrase string) error {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	if a.lockedKey == nil {
+		return nil
+	}
+
+	privateKey, err := a.lockedKey.decrypt(passphrase)
+	if err != nil {
+		return err
+	}
+
+	a.Settings.PrivateKey = privateKey
+	a.Settings.Passphrase = passphrase
+	a.lockedKey = nil
+	return nil
 }
 
 func (a *App) NeedsInit() bool {
+	a.m.Lock()
+	defer a.m.Unlock()
+
 	return a.Settings.AuthHeader == ""
 }
 
+// Connected returns the API client of the active profile, or ErrNotConnected if it has not connected since it was
+// loaded or switched to
+func (a *App) Connected() (TraderProvider, error) {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	if a.Provider == nil {
+		return nil, ErrNotConnected
+	}
+	return a.Provider, nil
+}
+
+// CurrentSettings returns a copy of the active profile's settings, safe to read while the profile is switched or unlocked
+func (a *App) CurrentSettings() Settings {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	return a.Settings
+}
+
+// UpdateSettings replaces the active profile's settings; call Save and Reconnect to apply them
+func (a *App) UpdateSettings(s Settings) {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	a.Settings = s
+}
+
 func (a *App) Connect() error {
 	a.m.Lock()
 	defer a.m.Unlock()
 
+	return a.connect()
+}
+
+// Reconnect closes any existing provider and connects again with the current settings
+func (a *App) Reconnect() error {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	a.disconnect()
+	return a.connect()
+}
+
+func (a *App) disconnect() {
+	if a.Provider == nil {
+		return
+	}
+	if err := a.Provider.Close(); err != nil {
+		log.Printf("could not close provider: %v", err)
+	}
+	a.Provider = nil
+}
+
+func (a *App) connect() error {
 	if a.Provider != nil {
 		return nil
 	}
 
 	var err error
 
-	opts := provider.DefaultRPCOpts(provider.MainnetGRPC)
+	transport := a.Settings.Transport
+	if transport == "" {
+		transport = TransportGRPC
+	}
+
+	var endpoint string
+	if transport != TransportFake {
+		endpoint, err = a.Settings.endpoint(transport)
+		if err != nil {
+			return err
+		}
+	}
+
+	opts := provider.DefaultRPCOpts(endpoint)
 	opts.PrivateKey = &a.Settings.PrivateKey
 	opts.AuthHeader = a.Settings.AuthHeader
-	opts.UseTLS = true
+	opts.UseTLS = a.Settings.useTLS()
 
 	// TODO: enhancement: WithBlock
-	a.Provider, err = provider.NewGRPCClientWithOpts(opts)
+	a.Provider, err = newProvider(transport, opts)
 	return err
 }
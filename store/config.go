@@ -0,0 +1,232 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"github.com/gagliardetto/solana-go"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	configVersion  = 3
+	configFileMode = 0600
+	configBackup   = ".bak"
+)
+
+// configFile is the on-disk settings schema shared by the loader and writer. Files without a version are treated as version 1.
+// Version 2 adds an optional passphrase-encrypted private key, which replaces the plaintext one when present.
+// Version 3 moves the settings into named profiles; older files are loaded as a single profile.
+type configFile struct {
+	Version       int             `json:"version"`
+	ActiveProfile string          `json:"activeProfile"`
+	Profiles      []profileConfig `json:"profiles"`
+
+	// Keys overrides key bindings by name, e.g. {"quit": ["q", "ctrl+q"]}
+	Keys map[string][]string `json:"keys,omitempty"`
+
+	// Favorites lists starred market names, shared by all profiles
+	Favorites []string `json:"favorites,omitempty"`
+
+	// TickerInterval is how often the tickers stage refreshes, as a duration string (e.g. "5s")
+	TickerInterval string `json:"tickerInterval,omitempty"`
+
+	// QueryTimeout bounds list queries such as open orders, as a duration string (e.g. "30s")
+	QueryTimeout string `json:"queryTimeout,omitempty"`
+
+	// PollInterval is how often list results such as open orders are refreshed, as a duration string (e.g. "10s"); a negative interval disables polling
+	PollInterval string `json:"pollInterval,omitempty"`
+
+	// OrderStatusMarkets lists the markets watched for order status notifications; favorites are watched when empty
+	OrderStatusMarkets []string `json:"orderStatusMarkets,omitempty"`
+}
+
+type profileConfig struct {
+	Name              string        `json:"name,omitempty"`
+	AuthHeader        string        `json:"authHeader"`
+	PrivateKey        string        `json:"privateKey,omitempty"`
+	EncryptedKey      *encryptedKey `json:"encryptedPrivateKey,omitempty"`
+	PublicKey         string        `json:"publicKey"`
+	OpenOrdersAddress string        `json:"openOrdersAddress"`
+	Project           string        `json:"project"`
+	Transport         string        `json:"transport,omitempty"`
+	Network           string        `json:"network,omitempty"`
+	Endpoint          string        `json:"endpoint,omitempty"`
+	UseTLS            *bool         `json:"useTLS,omitempty"`
+}
+
+func readConfig(filename string) (configFile, error) {
+	var c configFile
+
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return c, err
+	}
+
+	err = json.Unmarshal(b, &c)
+	if err != nil {
+		return c, fmt.Errorf("could not unmarshal json: %w", err)
+	}
+
+	if c.Version == 0 {
+		c.Version = 1
+	}
+	if c.Version > configVersion {
+		return c, fmt.Errorf("unsupported config version %v (latest supported: %v)", c.Version, configVersion)
+	}
+
+	if c.Version < 3 {
+		var p profileConfig
+		err = json.Unmarshal(b, &p)
+		if err != nil {
+			return c, fmt.Errorf("could not unmarshal json: %w", err)
+		}
+		p.Name = defaultProfile
+		c.ActiveProfile = defaultProfile
+		c.Profiles = []profileConfig{p}
+	}
+	return c, nil
+}
+
+// writeConfig atomically replaces filename with c, keeping a backup of the previous file
+func writeConfig(filename string, c configFile) error {
+	c.Version = configVersion
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = backupConfig(filename); err != nil {
+		return fmt.Errorf("could not back up previous config file: %w", err)
+	}
+	return writeFile(filename, b)
+}
+
+// writeFile replaces filename with b through a temporary file, so it is never left partially written
+func writeFile(filename string, b []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("could not create temporary file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if err = tmp.Chmod(configFileMode); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if _, err = tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
+
+func backupConfig(filename string) error {
+	b, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename+configBackup, b, configFileMode)
+}
+
+func settingsFromConfig(c profileConfig) (Settings, error) {
+	var err error
+	s := Settings{
+		AuthHeader: c.AuthHeader,
+		Endpoint:   c.Endpoint,
+	}
+
+	if c.PrivateKey != "" {
+		s.PrivateKey, err = solana.PrivateKeyFromBase58(c.PrivateKey)
+		if err != nil {
+			return s, fmt.Errorf("could not deserialize private key: %w", err)
+		}
+	}
+
+	if c.PublicKey != "" {
+		s.PublicKey, err = solana.PublicKeyFromBase58(c.PublicKey)
+		if err != nil {
+			return s, fmt.Errorf("could not deserialize public key: %w", err)
+		}
+	}
+
+	if c.OpenOrdersAddress != "" {
+		s.OpenOrdersAddress, err = solana.PublicKeyFromBase58(c.OpenOrdersAddress)
+		if err != nil {
+			return s, fmt.Errorf("could not deserialize open orders address: %w", err)
+		}
+	}
+
+	project, ok := pb.Project_value[c.Project]
+	if !ok {
+		return s, fmt.Errorf("could not deserialize project: %v", c.Project)
+	}
+	s.Project = pb.Project(project)
+
+	s.Transport, err = TransportFromString(c.Transport)
+	if err != nil {
+		return s, fmt.Errorf("could not deserialize transport: %w", err)
+	}
+
+	s.Network, err = NetworkFromString(c.Network)
+	if err != nil {
+		return s, fmt.Errorf("could not deserialize network: %w", err)
+	}
+
+	s.UseTLS = true
+	if c.UseTLS != nil {
+		s.UseTLS = *c.UseTLS
+	}
+	return s, nil
+}
+
+// configFromSettings serializes a profile. lockedKey is kept as-is for profiles that have not been unlocked.
+func configFromSettings(name string, s Settings, lockedKey *encryptedKey) (profileConfig, error) {
+	c := profileConfig{
+		Name:       name,
+		AuthHeader: s.AuthHeader,
+		Project:    s.Project.String(),
+		Transport:  string(s.Transport),
+		Network:    string(s.Network),
+		Endpoint:   s.Endpoint,
+	}
+	if len(s.PrivateKey) > 0 {
+		if s.Passphrase != "" {
+			encrypted, err := encryptPrivateKey(s.PrivateKey, s.Passphrase)
+			if err != nil {
+				return c, fmt.Errorf("could not encrypt private key: %w", err)
+			}
+			c.EncryptedKey = encrypted
+		} else {
+			c.PrivateKey = s.PrivateKey.String()
+		}
+	} else if lockedKey != nil {
+		c.EncryptedKey = lockedKey
+	}
+	if !s.PublicKey.IsZero() {
+		c.PublicKey = s.PublicKey.String()
+	}
+	if !s.OpenOrdersAddress.IsZero() {
+		c.OpenOrdersAddress = s.OpenOrdersAddress.String()
+	}
+	if s.Network == NetworkCustom {
+		useTLS := s.UseTLS
+		c.UseTLS = &useTLS
+	}
+	return c, nil
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveConfig(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.json")
+	a := &App{ConfigFile: filename, Profile: defaultProfile, Settings: Settings{
+		AuthHeader: "auth",
+		Transport:  TransportHTTP,
+		Network:    NetworkCustom,
+		Endpoint:   "localhost:1809",
+	}}
+	if err := a.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != configFileMode {
+		t.Errorf("mode = %v, want %v", mode, os.FileMode(configFileMode))
+	}
+	if _, err = os.Stat(filename + configBackup); !os.IsNotExist(err) {
+		t.Errorf("backup of a new config file: %v", err)
+	}
+
+	loaded, err := LoadFromFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := loaded.CurrentSettings(); s.AuthHeader != "auth" || s.Transport != TransportHTTP || s.Endpoint != "localhost:1809" || s.UseTLS {
+		t.Errorf("loaded %+v, want the saved settings", s)
+	}
+
+	previous, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.UpdateSettings(Settings{AuthHeader: "other"})
+	if err = a.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if backup, err := os.ReadFile(filename + configBackup); err != nil || string(backup) != string(previous) {
+		t.Errorf("backup = %q, %v; want the previous config file", backup, err)
+	}
+}
+
+func TestReadConfigV1(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(filename, []byte(`{"authHeader": "auth", "project": "P_OPENBOOK"}`), configFileMode); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := readConfig(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Version != 1 || len(c.Profiles) != 1 || c.Profiles[0].AuthHeader != "auth" || c.ActiveProfile != defaultProfile {
+		t.Errorf("read %+v, want the settings as the default profile", c)
+	}
+}
+
+func TestReadConfigUnsupportedVersion(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(filename, []byte(`{"version": 99}`), configFileMode); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := readConfig(filename); err == nil {
+		t.Error("config file from a later version accepted")
+	}
+}
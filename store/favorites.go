@@ -0,0 +1,23 @@
+package store
+
+// IsFavorite reports whether market is starred
+func (a *App) IsFavorite(market string) bool {
+	for _, favorite := range a.Favorites {
+		if favorite == market {
+			return true
+		}
+	}
+	return false
+}
+
+// ToggleFavorite stars or unstars market, returning whether it is now a favorite. Call Save to persist it.
+func (a *App) ToggleFavorite(market string) bool {
+	for i, favorite := range a.Favorites {
+		if favorite == market {
+			a.Favorites = append(a.Favorites[:i:i], a.Favorites[i+1:]...)
+			return false
+		}
+	}
+	a.Favorites = append(a.Favorites, market)
+	return true
+}
@@ -0,0 +1,119 @@
+package store
+
+import (
+	"fmt"
+	"github.com/bloXroute-Labs/solana-trader-client-go/provider"
+	"net"
+)
+
+type Network string
+
+const (
+	NetworkMainnet Network = "mainnet"
+	NetworkTestnet Network = "testnet"
+	NetworkDevnet  Network = "devnet"
+	NetworkCustom  Network = "custom"
+)
+
+var networkEndpoints = map[Network]map[Transport]string{
+	NetworkMainnet: {
+		TransportGRPC: provider.MainnetGRPC,
+		TransportHTTP: provider.MainnetHTTP,
+		TransportWS:   provider.MainnetWS,
+	},
+	NetworkTestnet: {
+		TransportGRPC: provider.TestnetGRPC,
+		TransportHTTP: provider.TestnetHTTP,
+		TransportWS:   provider.TestnetWS,
+	},
+	NetworkDevnet: {
+		TransportGRPC: provider.DevnetGRPC,
+		TransportHTTP: provider.DevnetHTTP,
+		TransportWS:   provider.DevnetWS,
+	},
+}
+
+func NetworkFromString(s string) (Network, error) {
+	switch n := Network(s); n {
+	case "":
+		return NetworkMainnet, nil
+	case NetworkMainnet, NetworkTestnet, NetworkDevnet, NetworkCustom:
+		return n, nil
+	default:
+		return "", fmt.Errorf("unknown network: %v", s)
+	}
+}
+
+// useTLS reports whether gRPC connections should use TLS: preset networks are fixed, custom endpoints are configurable
+func (s Settings) useTLS() bool {
+	switch s.Network {
+	case "", NetworkMainnet:
+		return true
+	case NetworkCustom:
+		return s.UseTLS
+	default:
+		return false
+	}
+}
+
+// ValidateEndpoint checks that a custom endpoint is a host:port pair
+func ValidateEndpoint(endpoint string) error {
+	host, port, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint %v: %w", endpoint, err)
+	}
+	if host == "" || port == "" {
+		return fmt.Errorf("invalid endpoint %v: expected host:port", endpoint)
+	}
+	return nil
+}
+
+// Environment describes the configured network for display
+func (s Settings) Environment() string {
+	network := s.Network
+	if network == "" {
+		network = NetworkMainnet
+	}
+	if network != NetworkCustom {
+		return string(network)
+	}
+
+	scheme := "insecure"
+	if s.UseTLS {
+		scheme = "tls"
+	}
+	return fmt.Sprintf("%v %v (%v)", network, s.Endpoint, scheme)
+}
+
+func (s Settings) endpoint(transport Transport) (string, error) {
+	network := s.Network
+	if network == "" {
+		network = NetworkMainnet
+	}
+
+	if network != NetworkCustom {
+		endpoint, ok := networkEndpoints[network][transport]
+		if !ok {
+			return "", fmt.Errorf("no %v endpoint for network %v", transport, network)
+		}
+		return endpoint, nil
+	}
+
+	if err := ValidateEndpoint(s.Endpoint); err != nil {
+		return "", err
+	}
+	switch transport {
+	case TransportHTTP:
+		if s.UseTLS {
+			return "https://" + s.Endpoint, nil
+		}
+		return "http://" + s.Endpoint, nil
+	case TransportWS:
+		if s.UseTLS {
+			return "wss://" + s.Endpoint + "/ws", nil
+		}
+		return "ws://" + s.Endpoint + "/ws", nil
+	default:
+		return s.Endpoint, nil
+	}
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"github.com/bloXroute-Labs/solana-trader-client-go/provider"
+	"testing"
+)
+
+func TestSettingsEndpoint(t *testing.T) {
+	tests := []struct {
+		settings  Settings
+		transport Transport
+		want      string
+		wantErr   bool
+	}{
+		{settings: Settings{}, transport: TransportGRPC, want: provider.MainnetGRPC},
+		{settings: Settings{Network: NetworkTestnet}, transport: TransportHTTP, want: provider.TestnetHTTP},
+		{settings: Settings{Network: NetworkCustom, Endpoint: "localhost:1809"}, transport: TransportGRPC, want: "localhost:1809"},
+		{settings: Settings{Network: NetworkCustom, Endpoint: "localhost:1809", UseTLS: true}, transport: TransportHTTP, want: "https://localhost:1809"},
+		{settings: Settings{Network: NetworkCustom, Endpoint: "localhost:1809"}, transport: TransportWS, want: "ws://localhost:1809/ws"},
+		{settings: Settings{Network: NetworkCustom, Endpoint: "localhost"}, transport: TransportGRPC, wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := test.settings.endpoint(test.transport)
+		if (err != nil) != test.wantErr || got != test.want {
+			t.Errorf("%v endpoint of %+v = %q, %v; want %q", test.transport, test.settings, got, err, test.want)
+		}
+	}
+}
+
+func TestSettingsTLS(t *testing.T) {
+	tests := []struct {
+		settings Settings
+		want     bool
+	}{
+		{settings: Settings{}, want: true},
+		{settings: Settings{Network: NetworkDevnet, UseTLS: true}, want: false},
+		{settings: Settings{Network: NetworkCustom}, want: false},
+		{settings: Settings{Network: NetworkCustom, UseTLS: true}, want: true},
+	}
+	for _, test := range tests {
+		if got := test.settings.useTLS(); got != test.want {
+			t.Errorf("useTLS of %+v = %v, want %v", test.settings, got, test.want)
+		}
+	}
+}
+
+func TestSettingsEnvironment(t *testing.T) {
+	if env := (Settings{}).Environment(); env != "mainnet" {
+		t.Errorf("environment = %q, want mainnet by default", env)
+	}
+	if env := (Settings{Network: NetworkCustom, Endpoint: "localhost:1809"}).Environment(); env != "custom localhost:1809 (insecure)" {
+		t.Errorf("environment = %q, want the custom endpoint", env)
+	}
+}
+
+func TestNetworkFromString(t *testing.T) {
+	if n, err := NetworkFromString(""); err != nil || n != NetworkMainnet {
+		t.Errorf("NetworkFromString(\"\") = %v, %v; want mainnet", n, err)
+	}
+	if _, err := NetworkFromString("localnet"); err == nil {
+		t.Error("unknown network accepted")
+	}
+}
@@ -0,0 +1,113 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+)
+
+const defaultProfile = "default"
+
+type profile struct {
+	name      string
+	settings  Settings
+	lockedKey *encryptedKey
+}
+
+type ProfileSummary struct {
+	Name        string
+	Environment string
+	Locked      bool
+	Active      bool
+}
+
+// Profiles lists the configured profiles in config file order
+func (a *App) Profiles() []ProfileSummary {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	a.storeActive()
+
+	summaries := make([]ProfileSummary, 0, len(a.profiles))
+	for _, p := range a.profiles {
+		summaries = append(summaries, ProfileSummary{
+			Name:        p.name,
+			Environment: p.settings.Environment(),
+			Locked:      p.lockedKey != nil,
+			Active:      p.name == a.Profile,
+		})
+	}
+	return summaries
+}
+
+// AddProfile creates an empty profile on the same network as the active one
+func (a *App) AddProfile(name string) error {
+	if name == "" {
+		return errors.New("profile name cannot be empty")
+	}
+
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	a.storeActive()
+	if a.findProfile(name) != nil {
+		return fmt.Errorf("profile %v already exists", name)
+	}
+
+	a.profiles = append(a.profiles, &profile{
+		name: name,
+		settings: Settings{
+			Transport: a.Settings.Transport,
+			Network:   a.Settings.Network,
+			Endpoint:  a.Settings.Endpoint,
+			UseTLS:    a.Settings.UseTLS,
+		},
+	})
+	return nil
+}
+
+// SwitchProfile tears down the current provider and makes name the active profile. The caller is responsible for unlocking and connecting again.
+func (a *App) SwitchProfile(name string) error {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	a.storeActive()
+	p := a.findProfile(name)
+	if p == nil {
+		return fmt.Errorf("unknown profile: %v", name)
+	}
+
+	a.disconnect()
+	a.load(p)
+	return nil
+}
+
+func (a *App) findProfile(name string) *profile {
+	for _, p := range a.profiles {
+		if p.name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+func (a *App) load(p *profile) {
+	a.Profile = p.name
+	a.Settings = p.settings
+	a.lockedKey = p.lockedKey
+}
+
+// storeActive copies the working settings back into the active profile, creating it if the config file had none.
+// Callers hold a.m.
+func (a *App) storeActive() {
+	if a.Profile == "" {
+		a.Profile = defaultProfile
+	}
+
+	p := a.findProfile(a.Profile)
+	if p == nil {
+		p = &profile{name: a.Profile}
+		a.profiles = append(a.profiles, p)
+	}
+	p.settings = a.Settings
+	p.lockedKey = a.lockedKey
+}
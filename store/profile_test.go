@@ -0,0 +1,63 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestProfilesConcurrentSwitch(t *testing.T) {
+	a := &App{ConfigFile: filepath.Join(t.TempDir(), "config.json"), Profile: defaultProfile}
+	for i := 0; i < 3; i++ {
+		if err := a.AddProfile(fmt.Sprintf("profile-%v", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(2)
+		go func(name string) {
+			defer wg.Done()
+			if err := a.SwitchProfile(name); err != nil {
+				t.Error(err)
+			}
+		}(fmt.Sprintf("profile-%v", i))
+		go func() {
+			defer wg.Done()
+			if n := len(a.Profiles()); n != 4 {
+				t.Errorf("%v profiles, want 4", n)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := a.Save(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSwitchProfileDisconnects(t *testing.T) {
+	a := &App{ConfigFile: filepath.Join(t.TempDir(), "config.json"), Profile: defaultProfile, Settings: Settings{Transport: TransportFake, AuthHeader: "auth"}}
+	if err := a.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Connected(); err != nil {
+		t.Fatalf("Connected() = %v after connecting", err)
+	}
+
+	if err := a.AddProfile("other"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.SwitchProfile("other"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Connected(); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Connected() = %v after switching, want ErrNotConnected", err)
+	}
+	if s := a.CurrentSettings(); s.AuthHeader != "" || s.Transport != TransportFake {
+		t.Errorf("CurrentSettings() = %+v, want the new profile on the same transport", s)
+	}
+}
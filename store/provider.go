@@ -0,0 +1,79 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/bloXroute-Labs/solana-trader-client-go/connections"
+	"github.com/bloXroute-Labs/solana-trader-client-go/provider"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+)
+
+// TraderProvider is the subset of the Trader API used by the application, so stages are not tied to a single transport
+type TraderProvider interface {
+	GetOpenOrders(ctx context.Context, market string, owner string, openOrdersAddress string, project pb.Project) (*pb.GetOpenOrdersResponse, error)
+	GetOrderbook(ctx context.Context, market string, limit uint32, project pb.Project) (*pb.GetOrderbookResponse, error)
+	GetMarkets(ctx context.Context) (*pb.GetMarketsResponse, error)
+	GetTickers(ctx context.Context, market string, project pb.Project) (*pb.GetTickersResponse, error)
+	GetTrades(ctx context.Context, market string, limit uint32, project pb.Project) (*pb.GetTradesResponse, error)
+	GetAccountBalance(ctx context.Context, owner string) (*pb.GetAccountBalanceResponse, error)
+	GetUnsettled(ctx context.Context, market, owner string, project pb.Project) (*pb.GetUnsettledResponse, error)
+	GetPrice(ctx context.Context, tokens []string) (*pb.GetPriceResponse, error)
+	GetQuotes(ctx context.Context, inToken, outToken string, inAmount, slippage float64, limit int32, projects []pb.Project) (*pb.GetQuotesResponse, error)
+
+	// Submit* calls sign transactions with the configured private key and return their signatures
+	SubmitOrder(ctx context.Context, owner, payer, market string, side pb.Side, types []pb.OrderType, amount, price float64, project pb.Project, opts provider.PostOrderOpts) (string, error)
+	SubmitReplaceOrder(ctx context.Context, orderID, owner, payer, market string, side pb.Side, types []pb.OrderType, amount, price float64, project pb.Project, opts provider.PostOrderOpts) (string, error)
+	SubmitCancelOrder(ctx context.Context, orderID string, side pb.Side, owner, market, openOrders string, project pb.Project, skipPreFlight bool) (string, error)
+	SubmitCancelByClientOrderID(ctx context.Context, clientOrderID uint64, owner, market, openOrders string, project pb.Project, skipPreFlight bool) (string, error)
+	SubmitCancelAll(ctx context.Context, market, owner string, openOrdersAddresses []string, project pb.Project, opts provider.SubmitOpts) (*pb.PostSubmitBatchResponse, error)
+	SubmitSettle(ctx context.Context, owner, market, baseTokenWallet, quoteTokenWallet, openOrders string, project pb.Project, skipPreFlight bool) (string, error)
+	SubmitRouteTradeSwap(ctx context.Context, request *pb.RouteTradeSwapRequest, opts provider.SubmitOpts) (*pb.PostSubmitBatchResponse, error)
+
+	// streams end when ctx is cancelled
+	GetOrderbookStream(ctx context.Context, markets []string, limit uint32, project pb.Project) (connections.Streamer[*pb.GetOrderbooksStreamResponse], error)
+	GetTradesStream(ctx context.Context, market string, limit uint32, project pb.Project) (connections.Streamer[*pb.GetTradesStreamResponse], error)
+	GetOrderStatusStream(ctx context.Context, market, owner string, project pb.Project) (connections.Streamer[*pb.GetOrderStatusStreamResponse], error)
+
+	Close() error
+}
+
+type Transport string
+
+const (
+	TransportGRPC Transport = "grpc"
+	TransportHTTP Transport = "http"
+	TransportWS   Transport = "ws"
+	TransportFake Transport = "fake"
+)
+
+var ErrStreamUnsupported = errors.New("streams are not supported over http; use the grpc or ws transport")
+
+// ErrOutcomeUnknown is returned when a submission is abandoned before its response, as its transaction may still land
+var ErrOutcomeUnknown = errors.New("outcome unknown, check open orders")
+
+func TransportFromString(s string) (Transport, error) {
+	switch t := Transport(s); t {
+	case "":
+		return TransportGRPC, nil
+	case TransportGRPC, TransportHTTP, TransportWS, TransportFake:
+		return t, nil
+	default:
+		return "", fmt.Errorf("unknown transport: %v", s)
+	}
+}
+
+func newProvider(transport Transport, opts provider.RPCOpts) (TraderProvider, error) {
+	switch transport {
+	case TransportGRPC:
+		return newGRPCProvider(opts)
+	case TransportHTTP:
+		return newHTTPProvider(opts), nil
+	case TransportWS:
+		return newWSProvider(opts)
+	case TransportFake:
+		return NewFakeProvider(), nil
+	default:
+		return nil, fmt.Errorf("unknown transport: %v", transport)
+	}
+}
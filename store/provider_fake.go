@@ -0,0 +1,450 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/bloXroute-Labs/solana-trader-client-go/connections"
+	"github.com/bloXroute-Labs/solana-trader-client-go/provider"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// FakeStreamInterval is the delay between updates on fake streams
+var FakeStreamInterval = 500 * time.Millisecond
+
+// FakeProvider is an in-memory TraderProvider for exercising stages without a Trader API connection
+type FakeProvider struct {
+	m sync.Mutex
+
+	// Err is returned by every call when set
+	Err error
+
+	// OpenOrders is keyed by market
+	OpenOrders map[string][]*pb.Order
+
+	// Orderbooks is keyed by market
+	Orderbooks map[string]*pb.GetOrderbookResponse
+
+	// Markets is keyed by market name
+	Markets map[string]*pb.Market
+
+	// Trades is keyed by market, most recent first
+	Trades map[string][]*pb.Trade
+
+	// Balances is keyed by owner address
+	Balances map[string][]*pb.TokenBalance
+
+	// Unsettled is keyed by market
+	Unsettled map[string][]*pb.UnsettledAccount
+
+	// Prices is keyed by token symbol or mint address
+	Prices map[string]*pb.TokenPrice
+
+	// Quotes is keyed by "inToken/outToken" as passed to GetQuotes
+	Quotes map[string][]*pb.ProjectQuote
+
+	// OrderStatuses is keyed by market, in the order the updates happened
+	OrderStatuses map[string][]*pb.GetOrderStatusResponse
+
+	// Swaps records submitted route swaps
+	Swaps []*pb.RouteTradeSwapRequest
+
+	orders     int
+	signatures int
+}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{
+		OpenOrders: make(map[string][]*pb.Order),
+		Orderbooks: make(map[string]*pb.GetOrderbookResponse),
+		Markets:    make(map[string]*pb.Market),
+		Trades:     make(map[string][]*pb.Trade),
+		Balances:   make(map[string][]*pb.TokenBalance),
+		Unsettled:  make(map[string][]*pb.UnsettledAccount),
+		Prices:     make(map[string]*pb.TokenPrice),
+		Quotes:     make(map[string][]*pb.ProjectQuote),
+
+		OrderStatuses: make(map[string][]*pb.GetOrderStatusResponse),
+	}
+}
+
+func (p *FakeProvider) GetOpenOrders(ctx context.Context, market string, owner string, openOrdersAddress string, project pb.Project) (*pb.GetOpenOrdersResponse, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if err := p.check(ctx); err != nil {
+		return nil, err
+	}
+	return &pb.GetOpenOrdersResponse{Orders: p.OpenOrders[market]}, nil
+}
+
+func (p *FakeProvider) GetOrderbook(ctx context.Context, market string, limit uint32, project pb.Project) (*pb.GetOrderbookResponse, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if err := p.check(ctx); err != nil {
+		return nil, err
+	}
+
+	orderbook, ok := p.Orderbooks[market]
+	if !ok {
+		return &pb.GetOrderbookResponse{Market: market}, nil
+	}
+
+	bids, asks := orderbook.Bids, orderbook.Asks
+	if limit > 0 && int(limit) < len(bids) {
+		bids = bids[:limit]
+	}
+	if limit > 0 && int(limit) < len(asks) {
+		asks = asks[:limit]
+	}
+	return &pb.GetOrderbookResponse{Market: orderbook.Market, MarketAddress: orderbook.MarketAddress, Bids: bids, Asks: asks}, nil
+}
+
+func (p *FakeProvider) GetMarkets(ctx context.Context) (*pb.GetMarketsResponse, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if err := p.check(ctx); err != nil {
+		return nil, err
+	}
+	return &pb.GetMarketsResponse{Markets: p.Markets}, nil
+}
+
+// GetTickers derives tickers from the best levels of Orderbooks; an empty market returns every orderbook
+func (p *FakeProvider) GetTickers(ctx context.Context, market string, project pb.Project) (*pb.GetTickersResponse, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if err := p.check(ctx); err != nil {
+		return nil, err
+	}
+
+	tickers := make([]*pb.Ticker, 0)
+	for name, orderbook := range p.Orderbooks {
+		if market != "" && name != market {
+			continue
+		}
+
+		ticker := &pb.Ticker{Market: name, MarketAddress: orderbook.MarketAddress, Project: project}
+		if len(orderbook.Bids) > 0 {
+			ticker.Bid, ticker.BidSize = orderbook.Bids[0].Price, orderbook.Bids[0].Size
+		}
+		if len(orderbook.Asks) > 0 {
+			ticker.Ask, ticker.AskSize = orderbook.Asks[0].Price, orderbook.Asks[0].Size
+		}
+		tickers = append(tickers, ticker)
+	}
+	return &pb.GetTickersResponse{Tickers: tickers}, nil
+}
+
+func (p *FakeProvider) GetTrades(ctx context.Context, market string, limit uint32, project pb.Project) (*pb.GetTradesResponse, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if err := p.check(ctx); err != nil {
+		return nil, err
+	}
+
+	trades := p.Trades[market]
+	if limit > 0 && int(limit) < len(trades) {
+		trades = trades[:limit]
+	}
+	return &pb.GetTradesResponse{Trades: trades}, nil
+}
+
+func (p *FakeProvider) GetAccountBalance(ctx context.Context, owner string) (*pb.GetAccountBalanceResponse, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if err := p.check(ctx); err != nil {
+		return nil, err
+	}
+	return &pb.GetAccountBalanceResponse{Tokens: p.Balances[owner]}, nil
+}
+
+func (p *FakeProvider) GetUnsettled(ctx context.Context, market, owner string, project pb.Project) (*pb.GetUnsettledResponse, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if err := p.check(ctx); err != nil {
+		return nil, err
+	}
+	return &pb.GetUnsettledResponse{Market: market, Unsettled: p.Unsettled[market]}, nil
+}
+
+func (p *FakeProvider) GetPrice(ctx context.Context, tokens []string) (*pb.GetPriceResponse, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if err := p.check(ctx); err != nil {
+		return nil, err
+	}
+
+	prices := make([]*pb.TokenPrice, 0, len(tokens))
+	for _, token := range tokens {
+		if price, ok := p.Prices[token]; ok {
+			prices = append(prices, price)
+		}
+	}
+	return &pb.GetPriceResponse{TokenPrices: prices}, nil
+}
+
+// GetQuotes returns the stored quotes for the pair as is, limited to the given projects and number of routes
+func (p *FakeProvider) GetQuotes(ctx context.Context, inToken, outToken string, inAmount, slippage float64, limit int32, projects []pb.Project) (*pb.GetQuotesResponse, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if err := p.check(ctx); err != nil {
+		return nil, err
+	}
+
+	quotes := make([]*pb.ProjectQuote, 0)
+	for _, quote := range p.Quotes[inToken+"/"+outToken] {
+		if !containsProject(projects, quote.Project) && !containsProject(projects, pb.Project_P_ALL) {
+			continue
+		}
+		routes := quote.Routes
+		if limit > 0 && int(limit) < len(routes) {
+			routes = routes[:limit]
+		}
+		quotes = append(quotes, &pb.ProjectQuote{Project: quote.Project, Routes: routes})
+	}
+	return &pb.GetQuotesResponse{InToken: inToken, OutToken: outToken, InAmount: inAmount, Quotes: quotes}, nil
+}
+
+// SubmitOrder rests the order in OpenOrders without matching it
+func (p *FakeProvider) SubmitOrder(ctx context.Context, owner, payer, market string, side pb.Side, types []pb.OrderType, amount, price float64, project pb.Project, opts provider.PostOrderOpts) (string, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if err := p.check(ctx); err != nil {
+		return "", err
+	}
+
+	p.orders++
+	p.OpenOrders[market] = append(p.OpenOrders[market], &pb.Order{
+		OrderID:          strconv.Itoa(p.orders),
+		Market:           market,
+		Side:             side,
+		Types:            types,
+		Price:            price,
+		RemainingSize:    amount,
+		ClientOrderID:    strconv.FormatUint(opts.ClientOrderID, 10),
+		OpenOrderAccount: opts.OpenOrdersAddress,
+	})
+	return p.signature(), nil
+}
+
+// SubmitReplaceOrder updates the resting order in place
+func (p *FakeProvider) SubmitReplaceOrder(ctx context.Context, orderID, owner, payer, market string, side pb.Side, types []pb.OrderType, amount, price float64, project pb.Project, opts provider.PostOrderOpts) (string, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if err := p.check(ctx); err != nil {
+		return "", err
+	}
+
+	for _, order := range p.OpenOrders[market] {
+		if order.OrderID == orderID {
+			order.Side = side
+			order.Types = types
+			order.Price = price
+			order.RemainingSize = amount
+			order.ClientOrderID = strconv.FormatUint(opts.ClientOrderID, 10)
+			return p.signature(), nil
+		}
+	}
+	return "", errors.New("order not found")
+}
+
+func (p *FakeProvider) SubmitCancelOrder(ctx context.Context, orderID string, side pb.Side, owner, market, openOrders string, project pb.Project, skipPreFlight bool) (string, error) {
+	return p.removeOrder(ctx, market, func(order *pb.Order) bool {
+		return order.OrderID == orderID
+	})
+}
+
+func (p *FakeProvider) SubmitCancelByClientOrderID(ctx context.Context, clientOrderID uint64, owner, market, openOrders string, project pb.Project, skipPreFlight bool) (string, error) {
+	return p.removeOrder(ctx, market, func(order *pb.Order) bool {
+		return order.ClientOrderID == strconv.FormatUint(clientOrderID, 10)
+	})
+}
+
+func (p *FakeProvider) SubmitCancelAll(ctx context.Context, market, owner string, openOrdersAddresses []string, project pb.Project, opts provider.SubmitOpts) (*pb.PostSubmitBatchResponse, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if err := p.check(ctx); err != nil {
+		return nil, err
+	}
+	delete(p.OpenOrders, market)
+	return &pb.PostSubmitBatchResponse{Transactions: []*pb.PostSubmitBatchResponseEntry{{Signature: p.signature(), Submitted: true}}}, nil
+}
+
+// SubmitSettle empties the unsettled amounts of the open orders account
+func (p *FakeProvider) SubmitSettle(ctx context.Context, owner, market, baseTokenWallet, quoteTokenWallet, openOrders string, project pb.Project, skipPreFlight bool) (string, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if err := p.check(ctx); err != nil {
+		return "", err
+	}
+
+	for _, account := range p.Unsettled[market] {
+		if account.Account != openOrders {
+			continue
+		}
+		if account.BaseToken != nil {
+			account.BaseToken.Amount = 0
+		}
+		if account.QuoteToken != nil {
+			account.QuoteToken.Amount = 0
+		}
+		return p.signature(), nil
+	}
+	return "", errors.New("open orders account not found")
+}
+
+// SubmitRouteTradeSwap records the swap in Swaps
+func (p *FakeProvider) SubmitRouteTradeSwap(ctx context.Context, request *pb.RouteTradeSwapRequest, opts provider.SubmitOpts) (*pb.PostSubmitBatchResponse, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if err := p.check(ctx); err != nil {
+		return nil, err
+	}
+	p.Swaps = append(p.Swaps, request)
+	return &pb.PostSubmitBatchResponse{Transactions: []*pb.PostSubmitBatchResponseEntry{{Signature: p.signature(), Submitted: true}}}, nil
+}
+
+// GetOrderbookStream re-emits the first market's orderbook every FakeStreamInterval, so changes made to Orderbooks show up as updates
+func (p *FakeProvider) GetOrderbookStream(ctx context.Context, markets []string, limit uint32, project pb.Project) (connections.Streamer[*pb.GetOrderbooksStreamResponse], error) {
+	if len(markets) == 0 {
+		return nil, errors.New("no markets provided")
+	}
+
+	var slot int64
+	return fakeStream(ctx, func() (*pb.GetOrderbooksStreamResponse, error) {
+		orderbook, err := p.GetOrderbook(ctx, markets[0], limit, project)
+		if err != nil {
+			return nil, err
+		}
+		slot++
+		return &pb.GetOrderbooksStreamResponse{Slot: slot, Orderbook: orderbook}, nil
+	}), nil
+}
+
+// GetTradesStream emits the trades added to Trades since the previous update every FakeStreamInterval
+func (p *FakeProvider) GetTradesStream(ctx context.Context, market string, limit uint32, project pb.Project) (connections.Streamer[*pb.GetTradesStreamResponse], error) {
+	p.m.Lock()
+	seen := len(p.Trades[market])
+	p.m.Unlock()
+
+	var slot int64
+	return fakeStream(ctx, func() (*pb.GetTradesStreamResponse, error) {
+		p.m.Lock()
+		defer p.m.Unlock()
+
+		if err := p.check(ctx); err != nil {
+			return nil, err
+		}
+
+		// Trades is most recent first, so new trades are at the front
+		trades := p.Trades[market]
+		if seen > len(trades) {
+			seen = len(trades)
+		}
+		added := trades[:len(trades)-seen]
+		seen = len(trades)
+		slot++
+		return &pb.GetTradesStreamResponse{Slot: slot, Trades: &pb.GetTradesResponse{Trades: added}}, nil
+	}), nil
+}
+
+// GetOrderStatusStream emits the owner's updates added to OrderStatuses since the previous one, one per FakeStreamInterval
+func (p *FakeProvider) GetOrderStatusStream(ctx context.Context, market, owner string, project pb.Project) (connections.Streamer[*pb.GetOrderStatusStreamResponse], error) {
+	p.m.Lock()
+	seen := len(p.OrderStatuses[market])
+	p.m.Unlock()
+
+	var slot int64
+	return fakeStream(ctx, func() (*pb.GetOrderStatusStreamResponse, error) {
+		p.m.Lock()
+		defer p.m.Unlock()
+
+		if err := p.check(ctx); err != nil {
+			return nil, err
+		}
+
+		slot++
+		statuses := p.OrderStatuses[market]
+		if seen >= len(statuses) {
+			seen = len(statuses)
+			return &pb.GetOrderStatusStreamResponse{Slot: slot}, nil
+		}
+		seen++
+		return &pb.GetOrderStatusStreamResponse{Slot: slot, OrderInfo: statuses[seen-1]}, nil
+	}), nil
+}
+
+func (p *FakeProvider) Close() error {
+	return nil
+}
+
+func (p *FakeProvider) removeOrder(ctx context.Context, market string, match func(*pb.Order) bool) (string, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if err := p.check(ctx); err != nil {
+		return "", err
+	}
+
+	orders := p.OpenOrders[market]
+	for i, order := range orders {
+		if match(order) {
+			p.OpenOrders[market] = append(orders[:i:i], orders[i+1:]...)
+			return p.signature(), nil
+		}
+	}
+	return "", errors.New("order not found")
+}
+
+// signature returns a unique placeholder transaction signature
+func (p *FakeProvider) signature() string {
+	p.signatures++
+	return fmt.Sprintf("fake-signature-%v", p.signatures)
+}
+
+// fakeStream calls next every FakeStreamInterval until ctx is cancelled
+func fakeStream[T any](ctx context.Context, next func() (T, error)) connections.Streamer[T] {
+	// the interval is read once, so tests can change it back while streams opened with it still run
+	interval := FakeStreamInterval
+	return func() (T, error) {
+		select {
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		case <-time.After(interval):
+			return next()
+		}
+	}
+}
+
+func containsProject(projects []pb.Project, project pb.Project) bool {
+	for _, p := range projects {
+		if p == project {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *FakeProvider) check(ctx context.Context) error {
+	if p.Err != nil {
+		return p.Err
+	}
+	return ctx.Err()
+}
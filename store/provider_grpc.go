@@ -0,0 +1,101 @@
+package store
+
+import (
+	"context"
+	"github.com/bloXroute-Labs/solana-trader-client-go/connections"
+	"github.com/bloXroute-Labs/solana-trader-client-go/provider"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+)
+
+type grpcProvider struct {
+	client *provider.GRPCClient
+}
+
+func newGRPCProvider(opts provider.RPCOpts) (TraderProvider, error) {
+	client, err := provider.NewGRPCClientWithOpts(opts)
+	if err != nil {
+		return nil, err
+	}
+	return grpcProvider{client: client}, nil
+}
+
+func (p grpcProvider) GetOpenOrders(ctx context.Context, market string, owner string, openOrdersAddress string, project pb.Project) (*pb.GetOpenOrdersResponse, error) {
+	return p.client.GetOpenOrders(ctx, market, owner, openOrdersAddress, project)
+}
+
+func (p grpcProvider) GetOrderbook(ctx context.Context, market string, limit uint32, project pb.Project) (*pb.GetOrderbookResponse, error) {
+	return p.client.GetOrderbook(ctx, market, limit, project)
+}
+
+func (p grpcProvider) GetMarkets(ctx context.Context) (*pb.GetMarketsResponse, error) {
+	return p.client.GetMarkets(ctx)
+}
+
+func (p grpcProvider) GetTickers(ctx context.Context, market string, project pb.Project) (*pb.GetTickersResponse, error) {
+	return p.client.GetTickers(ctx, market, project)
+}
+
+func (p grpcProvider) GetTrades(ctx context.Context, market string, limit uint32, project pb.Project) (*pb.GetTradesResponse, error) {
+	return p.client.GetTrades(ctx, market, limit, project)
+}
+
+func (p grpcProvider) GetAccountBalance(ctx context.Context, owner string) (*pb.GetAccountBalanceResponse, error) {
+	return p.client.GetAccountBalance(ctx, owner)
+}
+
+func (p grpcProvider) GetUnsettled(ctx context.Context, market, owner string, project pb.Project) (*pb.GetUnsettledResponse, error) {
+	return p.client.GetUnsettled(ctx, market, owner, project)
+}
+
+func (p grpcProvider) GetPrice(ctx context.Context, tokens []string) (*pb.GetPriceResponse, error) {
+	return p.client.GetPrice(ctx, tokens)
+}
+
+func (p grpcProvider) GetQuotes(ctx context.Context, inToken, outToken string, inAmount, slippage float64, limit int32, projects []pb.Project) (*pb.GetQuotesResponse, error) {
+	return p.client.GetQuotes(ctx, inToken, outToken, inAmount, slippage, limit, projects)
+}
+
+func (p grpcProvider) SubmitOrder(ctx context.Context, owner, payer, market string, side pb.Side, types []pb.OrderType, amount, price float64, project pb.Project, opts provider.PostOrderOpts) (string, error) {
+	return p.client.SubmitOrder(ctx, owner, payer, market, side, types, amount, price, project, opts)
+}
+
+func (p grpcProvider) SubmitReplaceOrder(ctx context.Context, orderID, owner, payer, market string, side pb.Side, types []pb.OrderType, amount, price float64, project pb.Project, opts provider.PostOrderOpts) (string, error) {
+	return p.client.SubmitReplaceOrder(ctx, orderID, owner, payer, market, side, types, amount, price, project, opts)
+}
+
+func (p grpcProvider) SubmitCancelOrder(ctx context.Context, orderID string, side pb.Side, owner, market, openOrders string, project pb.Project, skipPreFlight bool) (string, error) {
+	return p.client.SubmitCancelOrder(ctx, orderID, side, owner, market, openOrders, project, skipPreFlight)
+}
+
+func (p grpcProvider) SubmitCancelByClientOrderID(ctx context.Context, clientOrderID uint64, owner, market, openOrders string, project pb.Project, skipPreFlight bool) (string, error) {
+	return p.client.SubmitCancelByClientOrderID(ctx, clientOrderID, owner, market, openOrders, project, skipPreFlight)
+}
+
+func (p grpcProvider) SubmitCancelAll(ctx context.Context, market, owner string, openOrdersAddresses []string, project pb.Project, opts provider.SubmitOpts) (*pb.PostSubmitBatchResponse, error) {
+	return p.client.SubmitCancelAll(ctx, market, owner, openOrdersAddresses, project, opts)
+}
+
+func (p grpcProvider) SubmitSettle(ctx context.Context, owner, market, baseTokenWallet, quoteTokenWallet, openOrders string, project pb.Project, skipPreFlight bool) (string, error) {
+	return p.client.SubmitSettle(ctx, owner, market, baseTokenWallet, quoteTokenWallet, openOrders, project, skipPreFlight)
+}
+
+func (p grpcProvider) SubmitRouteTradeSwap(ctx context.Context, request *pb.RouteTradeSwapRequest, opts provider.SubmitOpts) (*pb.PostSubmitBatchResponse, error) {
+	return p.client.SubmitRouteTradeSwap(ctx, request, opts)
+}
+
+func (p grpcProvider) GetOrderbookStream(ctx context.Context, markets []string, limit uint32, project pb.Project) (connections.Streamer[*pb.GetOrderbooksStreamResponse], error) {
+	return p.client.GetOrderbookStream(ctx, markets, limit, project)
+}
+
+func (p grpcProvider) GetTradesStream(ctx context.Context, market string, limit uint32, project pb.Project) (connections.Streamer[*pb.GetTradesStreamResponse], error) {
+	return p.client.GetTradesStream(ctx, market, limit, project)
+}
+
+func (p grpcProvider) GetOrderStatusStream(ctx context.Context, market, owner string, project pb.Project) (connections.Streamer[*pb.GetOrderStatusStreamResponse], error) {
+	return p.client.GetOrderStatusStream(ctx, market, owner, project)
+}
+
+// Close is a no-op: the SDK does not expose the underlying gRPC connection
+func (p grpcProvider) Close() error {
+	return nil
+}
@@ -0,0 +1,178 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/bloXroute-Labs/solana-trader-client-go/connections"
+	"github.com/bloXroute-Labs/solana-trader-client-go/provider"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type httpProvider struct {
+	client *provider.HTTPClient
+}
+
+func newHTTPProvider(opts provider.RPCOpts) TraderProvider {
+	return httpProvider{client: provider.NewHTTPClientWithOpts(nil, opts)}
+}
+
+func (p httpProvider) GetOpenOrders(ctx context.Context, market string, owner string, openOrdersAddress string, project pb.Project) (*pb.GetOpenOrdersResponse, error) {
+	return withContext(ctx, func() (*pb.GetOpenOrdersResponse, error) {
+		return p.client.GetOpenOrders(market, owner, openOrdersAddress, project)
+	})
+}
+
+func (p httpProvider) GetOrderbook(ctx context.Context, market string, limit uint32, project pb.Project) (*pb.GetOrderbookResponse, error) {
+	return withContext(ctx, func() (*pb.GetOrderbookResponse, error) {
+		return p.client.GetOrderbook(market, limit, project)
+	})
+}
+
+func (p httpProvider) GetMarkets(ctx context.Context) (*pb.GetMarketsResponse, error) {
+	return withContext(ctx, func() (*pb.GetMarketsResponse, error) {
+		return p.client.GetMarkets()
+	})
+}
+
+func (p httpProvider) GetTickers(ctx context.Context, market string, project pb.Project) (*pb.GetTickersResponse, error) {
+	return withContext(ctx, func() (*pb.GetTickersResponse, error) {
+		return p.client.GetTickers(market, project)
+	})
+}
+
+func (p httpProvider) GetTrades(ctx context.Context, market string, limit uint32, project pb.Project) (*pb.GetTradesResponse, error) {
+	return withContext(ctx, func() (*pb.GetTradesResponse, error) {
+		return p.client.GetTrades(market, limit, project)
+	})
+}
+
+func (p httpProvider) GetAccountBalance(ctx context.Context, owner string) (*pb.GetAccountBalanceResponse, error) {
+	return withContext(ctx, func() (*pb.GetAccountBalanceResponse, error) {
+		return p.client.GetAccountBalance(owner)
+	})
+}
+
+func (p httpProvider) GetUnsettled(ctx context.Context, market, owner string, project pb.Project) (*pb.GetUnsettledResponse, error) {
+	return withContext(ctx, func() (*pb.GetUnsettledResponse, error) {
+		return p.client.GetUnsettled(market, owner, project)
+	})
+}
+
+func (p httpProvider) GetPrice(ctx context.Context, tokens []string) (*pb.GetPriceResponse, error) {
+	return withContext(ctx, func() (*pb.GetPriceResponse, error) {
+		return p.client.GetPrice(tokens)
+	})
+}
+
+func (p httpProvider) GetQuotes(ctx context.Context, inToken, outToken string, inAmount, slippage float64, limit int32, projects []pb.Project) (*pb.GetQuotesResponse, error) {
+	return withContext(ctx, func() (*pb.GetQuotesResponse, error) {
+		return p.client.GetQuotes(inToken, outToken, inAmount, slippage, limit, projects)
+	})
+}
+
+func (p httpProvider) SubmitOrder(ctx context.Context, owner, payer, market string, side pb.Side, types []pb.OrderType, amount, price float64, project pb.Project, opts provider.PostOrderOpts) (string, error) {
+	return submitWithContext(ctx, func() (string, error) {
+		return p.client.SubmitOrder(owner, payer, market, side, types, amount, price, project, opts)
+	})
+}
+
+func (p httpProvider) SubmitReplaceOrder(ctx context.Context, orderID, owner, payer, market string, side pb.Side, types []pb.OrderType, amount, price float64, project pb.Project, opts provider.PostOrderOpts) (string, error) {
+	return submitWithContext(ctx, func() (string, error) {
+		return p.client.SubmitReplaceOrder(orderID, owner, payer, market, side, types, amount, price, project, opts)
+	})
+}
+
+func (p httpProvider) SubmitCancelOrder(ctx context.Context, orderID string, side pb.Side, owner, market, openOrders string, project pb.Project, skipPreFlight bool) (string, error) {
+	return submitWithContext(ctx, func() (string, error) {
+		return p.client.SubmitCancelOrder(orderID, side, owner, market, openOrders, project, skipPreFlight)
+	})
+}
+
+func (p httpProvider) SubmitCancelByClientOrderID(ctx context.Context, clientOrderID uint64, owner, market, openOrders string, project pb.Project, skipPreFlight bool) (string, error) {
+	return submitWithContext(ctx, func() (string, error) {
+		return p.client.SubmitCancelByClientOrderID(clientOrderID, owner, market, openOrders, project, skipPreFlight)
+	})
+}
+
+func (p httpProvider) SubmitCancelAll(ctx context.Context, market, owner string, openOrdersAddresses []string, project pb.Project, opts provider.SubmitOpts) (*pb.PostSubmitBatchResponse, error) {
+	return submitWithContext(ctx, func() (*pb.PostSubmitBatchResponse, error) {
+		return p.client.SubmitCancelAll(market, owner, openOrdersAddresses, project, opts)
+	})
+}
+
+func (p httpProvider) SubmitSettle(ctx context.Context, owner, market, baseTokenWallet, quoteTokenWallet, openOrders string, project pb.Project, skipPreFlight bool) (string, error) {
+	return submitWithContext(ctx, func() (string, error) {
+		return p.client.SubmitSettle(owner, market, baseTokenWallet, quoteTokenWallet, openOrders, project, skipPreFlight)
+	})
+}
+
+func (p httpProvider) SubmitRouteTradeSwap(ctx context.Context, request *pb.RouteTradeSwapRequest, opts provider.SubmitOpts) (*pb.PostSubmitBatchResponse, error) {
+	return submitWithContext(ctx, func() (*pb.PostSubmitBatchResponse, error) {
+		return p.client.SubmitRouteTradeSwap(request, opts)
+	})
+}
+
+func (p httpProvider) GetOrderbookStream(ctx context.Context, markets []string, limit uint32, project pb.Project) (connections.Streamer[*pb.GetOrderbooksStreamResponse], error) {
+	return nil, ErrStreamUnsupported
+}
+
+func (p httpProvider) GetTradesStream(ctx context.Context, market string, limit uint32, project pb.Project) (connections.Streamer[*pb.GetTradesStreamResponse], error) {
+	return nil, ErrStreamUnsupported
+}
+
+func (p httpProvider) GetOrderStatusStream(ctx context.Context, market, owner string, project pb.Project) (connections.Streamer[*pb.GetOrderStatusStreamResponse], error) {
+	return nil, ErrStreamUnsupported
+}
+
+func (p httpProvider) Close() error {
+	return nil
+}
+
+// submitWithContext is withContext for calls submitting transactions. The HTTP call keeps running after returning
+// early, so the transaction may still land: the error says so rather than reporting a failure.
+func submitWithContext[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	v, err := withContext(ctx, fn)
+	if err != nil && err == ctx.Err() {
+		return v, fmt.Errorf("%w: %v", ErrOutcomeUnknown, err)
+	}
+	return v, err
+}
+
+// withContext runs a context-unaware HTTP call, returning early if the context finishes first
+func withContext[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	type result struct {
+		v   T
+		err error
+	}
+
+	ch := make(chan result, 1)
+	go func() {
+		v, err := fn()
+		ch <- result{v: v, err: statusError(err)}
+	}()
+
+	select {
+	case r := <-ch:
+		return r.v, r.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// statusError restores the gRPC status of an HTTP error: the client only keeps the response body, which carries the
+// status code and message
+func statusError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var body connections.HTTPError
+	if json.Unmarshal([]byte(err.Error()), &body) != nil || body.Code == 0 {
+		return err
+	}
+	return status.Error(codes.Code(body.Code), body.Message)
+}
@@ -0,0 +1,46 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestSubmitWithContextOutcomeUnknown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	block := make(chan struct{})
+	defer close(block)
+	_, err := submitWithContext(ctx, func() (string, error) {
+		<-block
+		return "signature", nil
+	})
+	if !errors.Is(err, ErrOutcomeUnknown) {
+		t.Errorf("err = %v, want %v", err, ErrOutcomeUnknown)
+	}
+}
+
+func TestSubmitWithContextError(t *testing.T) {
+	rejected := errors.New("rejected")
+	_, err := submitWithContext(context.Background(), func() (string, error) {
+		return "", rejected
+	})
+	if err != rejected {
+		t.Errorf("err = %v, want %v", err, rejected)
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	err := statusError(errors.New(`{"code":16,"message":"invalid auth header","details":[]}`))
+	if s, ok := status.FromError(err); !ok || s.Code() != codes.Unauthenticated || s.Message() != "invalid auth header" {
+		t.Errorf("err = %v, want an unauthenticated status", err)
+	}
+
+	plain := errors.New("bad gateway")
+	if err := statusError(plain); err != plain {
+		t.Errorf("err = %v, want %v", err, plain)
+	}
+}
@@ -0,0 +1,100 @@
+package store
+
+import (
+	"context"
+	"github.com/bloXroute-Labs/solana-trader-client-go/connections"
+	"github.com/bloXroute-Labs/solana-trader-client-go/provider"
+	pb "github.com/bloXroute-Labs/solana-trader-proto/api"
+)
+
+type wsProvider struct {
+	client *provider.WSClient
+}
+
+func newWSProvider(opts provider.RPCOpts) (TraderProvider, error) {
+	client, err := provider.NewWSClientWithOpts(opts)
+	if err != nil {
+		return nil, err
+	}
+	return wsProvider{client: client}, nil
+}
+
+func (p wsProvider) GetOpenOrders(ctx context.Context, market string, owner string, openOrdersAddress string, project pb.Project) (*pb.GetOpenOrdersResponse, error) {
+	return p.client.GetOpenOrders(ctx, market, owner, openOrdersAddress, project)
+}
+
+func (p wsProvider) GetOrderbook(ctx context.Context, market string, limit uint32, project pb.Project) (*pb.GetOrderbookResponse, error) {
+	return p.client.GetOrderbook(ctx, market, limit, project)
+}
+
+func (p wsProvider) GetMarkets(ctx context.Context) (*pb.GetMarketsResponse, error) {
+	return p.client.GetMarkets(ctx)
+}
+
+func (p wsProvider) GetTickers(ctx context.Context, market string, project pb.Project) (*pb.GetTickersResponse, error) {
+	return p.client.GetTickers(ctx, market, project)
+}
+
+func (p wsProvider) GetTrades(ctx context.Context, market string, limit uint32, project pb.Project) (*pb.GetTradesResponse, error) {
+	return p.client.GetTrades(ctx, market, limit, project)
+}
+
+func (p wsProvider) GetAccountBalance(ctx context.Context, owner string) (*pb.GetAccountBalanceResponse, error) {
+	return p.client.GetAccountBalance(ctx, owner)
+}
+
+func (p wsProvider) GetUnsettled(ctx context.Context, market, owner string, project pb.Project) (*pb.GetUnsettledResponse, error) {
+	return p.client.GetUnsettled(ctx, market, owner, project)
+}
+
+func (p wsProvider) GetPrice(ctx context.Context, tokens []string) (*pb.GetPriceResponse, error) {
+	return p.client.GetPrice(ctx, tokens)
+}
+
+func (p wsProvider) GetQuotes(ctx context.Context, inToken, outToken string, inAmount, slippage float64, limit int32, projects []pb.Project) (*pb.GetQuotesResponse, error) {
+	return p.client.GetQuotes(ctx, inToken, outToken, inAmount, slippage, limit, projects)
+}
+
+func (p wsProvider) SubmitOrder(ctx context.Context, owner, payer, market string, side pb.Side, types []pb.OrderType, amount, price float64, project pb.Project, opts provider.PostOrderOpts) (string, error) {
+	return p.client.SubmitOrder(ctx, owner, payer, market, side, types, amount, price, project, opts)
+}
+
+func (p wsProvider) SubmitReplaceOrder(ctx context.Context, orderID, owner, payer, market string, side pb.Side, types []pb.OrderType, amount, price float64, project pb.Project, opts provider.PostOrderOpts) (string, error) {
+	return p.client.SubmitReplaceOrder(ctx, orderID, owner, payer, market, side, types, amount, price, project, opts)
+}
+
+func (p wsProvider) SubmitCancelOrder(ctx context.Context, orderID string, side pb.Side, owner, market, openOrders string, project pb.Project, skipPreFlight bool) (string, error) {
+	return p.client.SubmitCancelOrder(ctx, orderID, side, owner, market, openOrders, project, skipPreFlight)
+}
+
+func (p wsProvider) SubmitCancelByClientOrderID(ctx context.Context, clientOrderID uint64, owner, market, openOrders string, project pb.Project, skipPreFlight bool) (string, error) {
+	return p.client.SubmitCancelByClientOrderID(ctx, clientOrderID, owner, market, openOrders, project, skipPreFlight)
+}
+
+func (p wsProvider) SubmitCancelAll(ctx context.Context, market, owner string, openOrdersAddresses []string, project pb.Project, opts provider.SubmitOpts) (*pb.PostSubmitBatchResponse, error) {
+	return p.client.SubmitCancelAll(ctx, market, owner, openOrdersAddresses, project, opts)
+}
+
+func (p wsProvider) SubmitSettle(ctx context.Context, owner, market, baseTokenWallet, quoteTokenWallet, openOrders string, project pb.Project, skipPreFlight bool) (string, error) {
+	return p.client.SubmitSettle(ctx, owner, market, baseTokenWallet, quoteTokenWallet, openOrders, project, skipPreFlight)
+}
+
+func (p wsProvider) SubmitRouteTradeSwap(ctx context.Context, request *pb.RouteTradeSwapRequest, opts provider.SubmitOpts) (*pb.PostSubmitBatchResponse, error) {
+	return p.client.SubmitRouteTradeSwap(ctx, request, opts)
+}
+
+func (p wsProvider) GetOrderbookStream(ctx context.Context, markets []string, limit uint32, project pb.Project) (connections.Streamer[*pb.GetOrderbooksStreamResponse], error) {
+	return p.client.GetOrderbooksStream(ctx, markets, limit, project)
+}
+
+func (p wsProvider) GetTradesStream(ctx context.Context, market string, limit uint32, project pb.Project) (connections.Streamer[*pb.GetTradesStreamResponse], error) {
+	return p.client.GetTradesStream(ctx, market, limit, project)
+}
+
+func (p wsProvider) GetOrderStatusStream(ctx context.Context, market, owner string, project pb.Project) (connections.Streamer[*pb.GetOrderStatusStreamResponse], error) {
+	return p.client.GetOrderStatusStream(ctx, market, owner, project)
+}
+
+func (p wsProvider) Close() error {
+	return p.client.Close()
+}
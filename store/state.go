@@ -0,0 +1,79 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+)
+
+// stateSuffix names the state file kept next to the config file. It holds what is remembered between sessions, rather
+// than configuration, so it is written without touching the config file or its backup.
+const stateSuffix = ".state"
+
+type stateFile struct {
+	// History is the list query history of each stage, most recent first
+	History map[string][][]string `json:"history,omitempty"`
+}
+
+func readState(filename string) (stateFile, error) {
+	var s stateFile
+
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return s, err
+	}
+
+	err = json.Unmarshal(b, &s)
+	if err != nil {
+		return s, fmt.Errorf("could not unmarshal json: %w", err)
+	}
+	return s, nil
+}
+
+func writeState(filename string, s stateFile) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(filename, b)
+}
+
+// loadState restores the state saved next to the config file; a missing or unreadable state file starts afresh
+func (a *App) loadState() {
+	a.History = make(map[string][][]string)
+	if a.ConfigFile == "" {
+		return
+	}
+
+	filename := a.ConfigFile + stateSuffix
+	s, err := readState(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		log.Printf("could not read state file (%v): %v", filename, err)
+		return
+	}
+	if s.History != nil {
+		a.History = s.History
+	}
+}
+
+// SetHistory replaces the query history of stage. Call SaveState to persist it.
+func (a *App) SetHistory(stage string, history [][]string) {
+	if a.History == nil {
+		a.History = make(map[string][][]string)
+	}
+	a.History[stage] = history
+}
+
+// SaveState writes the state to the file next to the config file
+func (a *App) SaveState() error {
+	if a.ConfigFile == "" {
+		return errors.New("no config file configured")
+	}
+	return writeState(a.ConfigFile+stateSuffix, stateFile{History: a.History})
+}
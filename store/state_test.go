@@ -0,0 +1,31 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSaveState(t *testing.T) {
+	dir := t.TempDir()
+	a := &App{ConfigFile: filepath.Join(dir, "config.json")}
+	a.SetHistory("openOrders", [][]string{{"SOL/USDC"}, {"SOL/USDT"}})
+	if err := a.SaveState(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := &App{ConfigFile: a.ConfigFile}
+	loaded.loadState()
+	if !reflect.DeepEqual(loaded.History, a.History) {
+		t.Errorf("history = %v, want %v", loaded.History, a.History)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "config.json"+stateSuffix {
+		t.Errorf("directory holds %v, want only the state file", entries)
+	}
+}